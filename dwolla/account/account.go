@@ -2,9 +2,7 @@
 package account
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
+	"context"
 
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
@@ -14,151 +12,81 @@ import (
 
 // Account represents a Dwolla master account that was estabslished on dwolla.com
 type Account struct {
-	Client client.DwollaClient
+	Client client.DwollaClient          `json:"-"`
 	Links  map[string]map[string]string `json:"_links"`
 	ID     string                       `json:"id"`   // Dwolla account ID
 	Name   string                       `json:"name"` // Dwolla account holder name
 }
 
 // RetrieveAccount returns the Dwolla master account
-func RetrieveAccount(c client.DwollaClient) (*Account, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+func RetrieveAccount(ctx context.Context, c client.DwollaClient) (*Account, error) {
+	links, err := c.Links(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.Links()["account"]["href"], nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating get root request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != 200 {
-		return nil, errors.New(res.Status)
+		return nil, errors.Wrap(err, "failed to get client links")
 	}
 	acc := &Account{}
-	decoder := json.NewDecoder(res.Body)
-	err = decoder.Decode(acc)
+	_, err = client.Do(ctx, c, "GET", links["account"]["href"], nil, acc)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse json response")
+		return nil, err
 	}
 	acc.Client = c
 	return acc, nil
 }
 
 // CreateFundingSource adds a funding resource to the master dwolla account
-func (a *Account) CreateFundingSource(fundingResource *funding.Resource) error {
-	var c = a.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(fundingResource)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling funding resource into req body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/funding-sources", bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return nil
-	case 400:
-		return errors.Wrap(errors.New("Bad Request"), "duplicate funding resource of validation error")
-	case 403:
-		return errors.Wrap(errors.New("Unauthorized"), "not authorized to create funding resource")
-	default:
-		return errors.New(res.Status)
-	}
+// and returns the ID and href of the new funding source.
+func (a *Account) CreateFundingSource(ctx context.Context, fundingResource *funding.Resource) (*client.Created, error) {
+	return client.Create(ctx, a.Client, "/funding-sources", fundingResource)
 }
 
-// ListFundingResources retrieves a list of funding sources that belong to an Account
-func (a *Account) ListFundingResources() ([]funding.Resource, error) {
-	var c = a.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/accounts/"+a.ID+"/funding-sources", nil)
+// CreateAndGetFundingSource adds a funding resource like CreateFundingSource,
+// then retrieves the created funding source.
+func (a *Account) CreateAndGetFundingSource(ctx context.Context, fundingResource *funding.Resource) (*funding.Resource, error) {
+	created, err := a.CreateFundingSource(ctx, fundingResource)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
+		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+	return funding.GetFundingSource(ctx, a.Client, created.ID)
+}
+
+// ListFundingResources retrieves a page of the funding sources that belong to an Account.
+// It also returns the number of funding sources in the whole list.
+func (a *Account) ListFundingResources(ctx context.Context, opts *client.ListOptions) ([]funding.Resource, int, error) {
+	sources, page, err := funding.ListPage(ctx, a.Client, a.fundingSourcesPath(opts))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla server")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &funding.ListResourcesResponse{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		return body.Embedded["funding-sources"], nil
-	case 403:
-		return nil, errors.New("not authorized to list funding sources")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, 0, err
 	}
+	return sources, page.Total, nil
+}
+
+// IterFundingResources returns an iterator over all the funding sources that belong to an Account.
+// opts selects the size of the pages and where the iteration starts.
+func (a *Account) IterFundingResources(ctx context.Context, opts *client.ListOptions) *funding.Iterator {
+	return funding.NewIterator(ctx, a.Client, a.fundingSourcesPath(opts))
+}
+
+func (a *Account) fundingSourcesPath(opts *client.ListOptions) string {
+	return client.WithQuery("/accounts/"+a.ID+"/funding-sources", opts.Values())
 }
 
 // TODO : Add ListAndSearchTransfers method
 
-// ListMassPayments retrieves an Account’s list of previously created mass payments
-func (a *Account) ListMassPayments() ([]masspayment.MassPayment, error) {
-	var c = a.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+// ListMassPayments retrieves a page of an Account’s previously created mass payments.
+// It also returns the number of mass payments in the whole list.
+func (a *Account) ListMassPayments(ctx context.Context, opts *client.ListOptions) ([]masspayment.MassPayment, int, error) {
+	payments, page, err := masspayment.ListPage(ctx, a.Client, a.massPaymentsPath(opts))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/accounts"+a.ID+"/mass-payments", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla server")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &masspayment.ListMassPaymentsResponse{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		return body.Embedded["mass-payments"], nil
-	case 403:
-		return nil, errors.New("not authorized to list mass payments")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, 0, err
 	}
+	return payments, page.Total, nil
+}
+
+// IterMassPayments returns an iterator over all of an Account’s previously created mass payments.
+// opts selects the size of the pages and where the iteration starts.
+func (a *Account) IterMassPayments(ctx context.Context, opts *client.ListOptions) *masspayment.Iterator {
+	return masspayment.NewIterator(ctx, a.Client, a.massPaymentsPath(opts))
+}
+
+func (a *Account) massPaymentsPath(opts *client.ListOptions) string {
+	return client.WithQuery("/accounts/"+a.ID+"/mass-payments", opts.Values())
 }
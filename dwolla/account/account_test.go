@@ -1,6 +1,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -150,7 +151,7 @@ type mockClient struct {
 func (m *mockClient) RootURL() string {
 	return m.rootURL
 }
-func (m *mockClient) Root() (map[string]map[string]string, error) {
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	account := make(map[string]string)
 	self := make(map[string]string)
@@ -161,10 +162,10 @@ func (m *mockClient) Root() (map[string]map[string]string, error) {
 	return mockLinks, nil
 }
 
-func (m *mockClient) AuthToken() (string, error) {
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
 	return m.authToken, nil
 }
-func (m *mockClient) Links() map[string]map[string]string {
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	self := make(map[string]string)
 	account := make(map[string]string)
@@ -172,9 +173,9 @@ func (m *mockClient) Links() map[string]map[string]string {
 	self["href"] = m.rootURL
 	mockLinks["self"] = self
 	mockLinks["account"] = account
-	return mockLinks
+	return mockLinks, nil
 }
-func (m *mockClient) SetAccessToken() error {
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
 	return nil
 }
 
@@ -182,6 +183,11 @@ func (m *mockClient) SetRootURL(url string) {
 	m.rootURL = url
 }
 
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
 func stubAccount() *Account {
 	mock := &mockClient{
 		Env:          "Test",
@@ -215,7 +221,7 @@ func TestRetrieveAccount(t *testing.T) {
 	}))
 	defer ts.Close()
 	stubAcc.Client.SetRootURL(ts.URL)
-	account, err := RetrieveAccount(stubAcc.Client)
+	account, err := RetrieveAccount(context.Background(), stubAcc.Client)
 	if err != nil {
 		t.Error(err)
 	}
@@ -225,6 +231,7 @@ func TestRetrieveAccount(t *testing.T) {
 func TestCreateFundingSource(t *testing.T) {
 	stubAcc := stubAccount()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/funding-sources/49dbaa24-1580-4b1c-8b58-24e26656fa31")
 		w.WriteHeader(201)
 		fmt.Fprint(w, mockAccount)
 	}))
@@ -236,7 +243,7 @@ func TestCreateFundingSource(t *testing.T) {
 		AccountNumber:   "123456789",
 		BankAccountType: "checking",
 	}
-	err := stubAcc.CreateFundingSource(fundingSource)
+	_, err := stubAcc.CreateFundingSource(context.Background(), fundingSource)
 	if err != nil {
 		t.Error(err)
 	}
@@ -250,7 +257,7 @@ func TestListFundingSources(t *testing.T) {
 	defer ts.Close()
 
 	stubAcc.Client.SetRootURL(ts.URL)
-	_, err := stubAcc.ListFundingResources()
+	_, _, err := stubAcc.ListFundingResources(context.Background(), nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -264,7 +271,7 @@ func TestListMassPayments(t *testing.T) {
 	defer ts.Close()
 
 	stubAcc.Client.SetRootURL(ts.URL)
-	_, err := stubAcc.ListMassPayments()
+	_, _, err := stubAcc.ListMassPayments(context.Background(), nil)
 	if err != nil {
 		t.Error(err)
 	}
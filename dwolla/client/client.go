@@ -3,11 +3,15 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -16,20 +20,42 @@ import (
 type DwollaClient interface {
 	SetRootURL(URL string)
 	RootURL() string
-	Root() (map[string]map[string]string, error)
-	AuthToken() (string, error)
-	SetAccessToken() error
-	Links() map[string]map[string]string
+	Root(ctx context.Context) (map[string]map[string]string, error)
+	AuthToken(ctx context.Context) (string, error)
+	SetAccessToken(ctx context.Context) error
+	Links(ctx context.Context) (map[string]map[string]string, error)
+	Send(req *http.Request) (*http.Response, error)
 }
 
+// tokenExpiryMargin is how long before its expiry a cached token is refreshed.
+const tokenExpiryMargin = time.Minute
+
 // Client represents a client for the dwolla REST API.
+// A Client is safe for concurrent use by multiple goroutines.
 type Client struct {
 	Env          string                       // either sandbox or production
 	ClientID     string                       // Dwolla client ID
 	ClientSecret string                       // Dwolla Client Secret
+	httpClient   *http.Client                 // Client used to send requests. Defaults to a zero-value http.Client
+	userAgent    string                       // User-Agent header sent with every request, if set
+	retryPolicy  RetryPolicy                  // Policy used to retry failed requests
+	autoIdemKeys bool                         // Generate an Idempotency-Key for POST requests without one
+	mu           sync.Mutex                   // Guards the fields below
 	authToken    string                       // Dowlla Auth token that expires in 1 hour
+	expiresAt    time.Time                    // Time at which authToken expires
+	refresh      *tokenRefresh                // In-flight token refresh shared by concurrent callers
 	rootURL      string                       // Root url of dwolla api. Differs according to Env
 	links        map[string]map[string]string // Links to account resources
+	sentKeys     map[string]time.Time         // Idempotency keys sent to dwolla and when
+	keysPrunedAt time.Time                    // Last time expired keys were removed from sentKeys
+}
+
+// tokenRefresh is a single request to the token endpoint that
+// concurrent callers wait on instead of making their own.
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
 }
 
 // Link represents URL to and endpoint in dwolla api
@@ -43,15 +69,29 @@ type Link struct {
 type DwollaError struct {
 	Links       map[string]Link `json:"_links"`
 	Code        string          `json:"code"`
+	Message     string          `json:"message"`
 	Description string          `json:"description"`
 }
 
-// CreateClient creates a new Dwolla Client
-func CreateClient(env string, clientID string, clientSecret string) (DwollaClient, error) {
+// CreateClient creates a new Dwolla Client.
+// No request is made until the client is first used, unless the
+// WithEagerDiscovery option is given.
+func CreateClient(env string, clientID string, clientSecret string, opts ...Option) (DwollaClient, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	c := &Client{
 		Env:          env,
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
+		httpClient:   o.buildHTTPClient(),
+		userAgent:    o.userAgent,
+		retryPolicy:  DefaultRetryPolicy,
+		autoIdemKeys: o.autoIdempotency,
+	}
+	if o.retryPolicy != nil {
+		c.retryPolicy = *o.retryPolicy
 	}
 	switch env {
 	case "sandbox":
@@ -61,7 +101,13 @@ func CreateClient(env string, clientID string, clientSecret string) (DwollaClien
 	default:
 		c.SetRootURL("https://api-sandbox.dwolla.com")
 	}
-	_, err := c.Root()
+	if o.rootURL != "" {
+		c.SetRootURL(o.rootURL)
+	}
+	if !o.eagerDiscover {
+		return c, nil
+	}
+	_, err := c.Root(context.Background())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get client links")
 	}
@@ -70,76 +116,245 @@ func CreateClient(env string, clientID string, clientSecret string) (DwollaClien
 
 // SetRootURL sets the rootURL of the client to the given value
 func (c *Client) SetRootURL(URL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.rootURL = URL
 }
 
 // RootURL returns the root url of the clinet
 func (c *Client) RootURL() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.rootURL
 }
 
-// AuthToken returns the current auth token
-func (c *Client) AuthToken() (string, error) {
-	err := c.SetAccessToken()
+// AuthToken returns the cached auth token.
+// The token is refreshed when it is missing or about to expire.
+func (c *Client) AuthToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.authToken != "" && time.Now().Add(tokenExpiryMargin).Before(c.expiresAt) {
+		token := c.authToken
+		c.mu.Unlock()
+		return token, nil
+	}
+	c.mu.Unlock()
+	token, err := c.refreshToken(ctx)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to refresh access token")
 	}
-	return c.authToken, nil
+	return token, nil
 }
 
 // SetAccessToken makes a request to dwolla to get an access token. Then sets this token into the current client.
-func (c *Client) SetAccessToken() error {
-	hc := &http.Client{}
-	req, err := http.NewRequest("POST", c.RootURL()+"/token", bytes.NewReader([]byte("grant_type=client_credentials")))
+func (c *Client) SetAccessToken(ctx context.Context) error {
+	_, err := c.refreshToken(ctx)
+	return err
+}
+
+// refreshToken fetches a new access token. Callers that arrive while a refresh
+// is in flight wait for its result instead of requesting another token.
+func (c *Client) refreshToken(ctx context.Context) (string, error) {
+	for {
+		c.mu.Lock()
+		r := c.refresh
+		if r == nil {
+			r = &tokenRefresh{done: make(chan struct{})}
+			c.refresh = r
+			c.mu.Unlock()
+			token, expiresIn, err := c.fetchToken(ctx)
+			c.mu.Lock()
+			r.token, r.err = token, err
+			if err == nil {
+				c.authToken = token
+				c.expiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+			}
+			c.refresh = nil
+			c.mu.Unlock()
+			close(r.done)
+			return token, err
+		}
+		c.mu.Unlock()
+		select {
+		case <-r.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		// The shared refresh may have failed only because the caller that
+		// started it gave up. Try again with our own context in that case.
+		if r.err != nil && ctx.Err() == nil && (errors.Cause(r.err) == context.Canceled || errors.Cause(r.err) == context.DeadlineExceeded) {
+			continue
+		}
+		return r.token, r.err
+	}
+}
+
+// invalidateToken drops the cached token if it is still the given one,
+// so that the next call to AuthToken fetches a new token.
+func (c *Client) invalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.authToken == token {
+		c.authToken = ""
+		c.expiresAt = time.Time{}
+	}
+}
+
+// fetchToken requests a new access token from the token endpoint.
+func (c *Client) fetchToken(ctx context.Context) (string, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.RootURL()+"/token", bytes.NewReader([]byte("grant_type=client_credentials")))
 	if err != nil {
-		return errors.Wrap(err, "error creating get token request")
+		return "", 0, errors.Wrap(err, "error creating get token request")
 	}
 	req.Header.Add("Authorization", fmt.Sprintf("Basic %v", base64.StdEncoding.EncodeToString([]byte(c.ClientID+":"+c.ClientSecret))))
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := hc.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return errors.Wrap(err, "error making request to dowlla api")
+		return "", 0, errors.Wrap(err, "error making request to dowlla api")
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		return "", 0, NewAPIError(resp)
 	}
-	defer resp.Body.Close()
+	token, expiresIn, err := decodeAuthTokenResp(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "failed to get access token from dwolla api")
+		return "", 0, errors.Wrap(err, "failed to decode access token")
 	}
-	token, err := decodeAuthTokenResp(resp.Body)
-	if err != nil {
-		return errors.Wrap(err, "failed to decode access token")
+	return token, expiresIn, nil
+}
+
+// Send makes an authenticated request to dwolla api.
+// If dwolla rejects the access token with a 401 the token is refreshed
+// and the request is sent once more. Failed attempts are retried
+// according to the retry policy of the client.
+func (c *Client) Send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idem := idempotencyFromContext(ctx)
+	key := c.idempotencyKey(req, idem)
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		token, err := c.AuthToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r, err := rewind(req, attempt > 1 || refreshed)
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+		res, err := c.do(r)
+		if err == nil && res.StatusCode == http.StatusUnauthorized && !refreshed && (req.Body == nil || req.GetBody != nil) {
+			res.Body.Close()
+			c.invalidateToken(token)
+			refreshed = true
+			attempt--
+			continue
+		}
+		if key != "" && c.markKeySent(key) && idem != nil {
+			idem.KeyReused = true
+		}
+		if attempt >= c.retryPolicy.MaxAttempts || !retryable(req, res, err) {
+			return res, err
+		}
+		delay, ok := c.retryPolicy.backoff(attempt, res)
+		if !ok {
+			return res, err
+		}
+		event := RetryEvent{Request: req, Attempt: attempt, Delay: delay, Err: err}
+		if res != nil {
+			event.StatusCode = res.StatusCode
+			io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxErrorBodySize))
+			res.Body.Close()
+		}
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(event)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// idempotencyKey sets the Idempotency-Key header of a POST request and returns its value.
+// A key is generated when neither the request nor idem carry one and either idem is
+// given or the client generates keys automatically.
+func (c *Client) idempotencyKey(req *http.Request, idem *Idempotency) string {
+	if req.Method != http.MethodPost {
+		return ""
+	}
+	key := req.Header.Get("Idempotency-Key")
+	if key == "" && idem != nil {
+		key = idem.Key
 	}
-	c.authToken = token
-	return nil
+	if key == "" && (idem != nil || c.autoIdemKeys) {
+		key = NewIdempotencyKey()
+	}
+	if idem != nil {
+		idem.Key = key
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	return key
+}
+
+// rewind returns req, or a copy of it with a fresh body when it is sent again.
+func rewind(req *http.Request, again bool) (*http.Request, error) {
+	if !again {
+		return req, nil
+	}
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to rewind request body")
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// do sends req with the configured http.Client and User-Agent.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	hc := c.httpClient
+	if hc == nil {
+		hc = &http.Client{}
+	}
+	return hc.Do(req)
 }
 
 // Links returns the root links of the client.
-func (c *Client) Links() map[string]map[string]string {
-	return c.links
+// The links are fetched from the root endpoint if they were not discovered yet.
+func (c *Client) Links(ctx context.Context) (map[string]map[string]string, error) {
+	c.mu.Lock()
+	links := c.links
+	c.mu.Unlock()
+	if links != nil {
+		return links, nil
+	}
+	return c.Root(ctx)
 }
 
 // Root returns the resources avaliable by dwolla api
-func (c *Client) Root() (map[string]map[string]string, error) {
-	hc := &http.Client{}
-	req, err := http.NewRequest("GET", c.RootURL(), nil)
+func (c *Client) Root(ctx context.Context) (map[string]map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.RootURL(), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating get root request")
 	}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "error refreshing access token")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
 	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+	res, err := c.Send(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "error making request to root endpoint")
 	}
 	defer res.Body.Close()
 	if res.StatusCode != 200 {
-		return nil, errors.New(res.Status)
+		return nil, NewAPIError(res)
 	}
 	resources := make(map[string]map[string]map[string]string)
 	decoder := json.NewDecoder(res.Body)
@@ -147,11 +362,13 @@ func (c *Client) Root() (map[string]map[string]string, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse json response")
 	}
+	c.mu.Lock()
 	c.links = resources["_links"]
-	return c.links, nil
+	c.mu.Unlock()
+	return resources["_links"], nil
 }
 
-func decodeAuthTokenResp(r io.Reader) (string, error) {
+func decodeAuthTokenResp(r io.Reader) (string, int, error) {
 	type authResponse struct {
 		TokenType string `json:"token_type"`
 		Token     string `json:"access_token"`
@@ -161,7 +378,7 @@ func decodeAuthTokenResp(r io.Reader) (string, error) {
 	tokenResp := &authResponse{}
 	err := d.Decode(tokenResp)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to decode json response")
+		return "", 0, errors.Wrap(err, "failed to decode json response")
 	}
-	return tokenResp.Token, nil
+	return tokenResp.Token, tokenResp.ExpiresIn, nil
 }
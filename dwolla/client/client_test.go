@@ -4,10 +4,15 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var mockToken = `
@@ -43,7 +48,7 @@ func TestSetAuthToken(t *testing.T) {
 		rootURL:      "http://localhost:8080",
 	}
 	mock.SetRootURL(ts.URL)
-	token, err := mock.AuthToken()
+	token, err := mock.AuthToken(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -62,8 +67,147 @@ func TestRoot(t *testing.T) {
 		rootURL:      "http://localhost:8080",
 	}
 	mock.SetRootURL(ts.URL)
-	_, err := mock.Root()
+	_, err := mock.Root(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+func TestAuthTokenCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockToken)
+	}))
+	defer ts.Close()
+	mock := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+	}
+	mock.SetRootURL(ts.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := mock.AuthToken(ctx)
+	if err == nil {
+		t.Error("expected an error for a canceled context")
+	}
+}
+
+func TestAuthTokenCanceledMidCall(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+	mock := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+	}
+	mock.SetRootURL(ts.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := mock.AuthToken(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected the token fetch to be aborted promptly, took %s", d)
+	}
+}
+
+func TestSendDeadlineMidCall(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+	mock := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+	}
+	mock.SetRootURL(ts.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := mock.Root(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected the request to be aborted promptly, took %s", d)
+	}
+}
+
+func TestAuthTokenCached(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, mockToken)
+	}))
+	defer ts.Close()
+	mock := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+	}
+	mock.SetRootURL(ts.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := mock.AuthToken(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	_, err := mock.AuthToken(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected 1 token request, got %d", n)
+	}
+}
+
+func TestSendRetriesUnauthorized(t *testing.T) {
+	var tokens, requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			n := atomic.AddInt32(&tokens, 1)
+			fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": 3600}`, n)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, mockRoot)
+	}))
+	defer ts.Close()
+	mock := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+	}
+	mock.SetRootURL(ts.URL)
+	_, err := mock.Root(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if tokens != 2 || requests != 2 {
+		t.Errorf("expected 2 token requests and 2 requests, got %d and %d", tokens, requests)
+	}
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// Created identifies a resource created by a POST request to dwolla api.
+type Created struct {
+	ID   string // ID of the new resource
+	Href string // Full url of the new resource, as sent in the Location header
+}
+
+// NewCreated reads the Location header of the response to a create request.
+func NewCreated(res *http.Response) (*Created, error) {
+	href := res.Header.Get("Location")
+	if href == "" {
+		return nil, errors.New("dwolla response has no Location header")
+	}
+	return &Created{
+		ID:   path.Base(href),
+		Href: href,
+	}, nil
+}
+
+// Create sends a POST request that creates a resource and returns the
+// location of the new resource. See Do for the meaning of its arguments.
+// The request is sent with the Idempotency-Key of a ctx made with
+// WithIdempotency, so that the creation can be safely repeated.
+func Create(ctx context.Context, c DwollaClient, hrefOrPath string, body interface{}) (*Created, error) {
+	res, err := Do(ctx, c, "POST", hrefOrPath, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewCreated(res)
+}
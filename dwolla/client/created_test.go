@@ -0,0 +1,21 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewCreated(t *testing.T) {
+	res := &http.Response{Header: http.Header{"Location": []string{"https://api-sandbox.dwolla.com/transfers/d76265cd-0951-e511-80da-0aa34a9b2388"}}}
+	created, err := NewCreated(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "d76265cd-0951-e511-80da-0aa34a9b2388" || created.Href != res.Header.Get("Location") {
+		t.Errorf("unexpected created resource %+v", created)
+	}
+	_, err = NewCreated(&http.Response{Header: http.Header{}})
+	if err == nil {
+		t.Error("expected an error without a Location header")
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// halJSON is the media type of dwolla api requests and responses.
+const halJSON = "application/vnd.dwolla.v1.hal+json"
+
+// Do sends a request to dwolla api and decodes its JSON response into out.
+//
+// hrefOrPath is either a full href, as found in the _links of a resource,
+// or a path relative to the root url of c such as "/customers".
+// body is encoded as JSON, unless it is nil or an io.Reader which is sent as is.
+// out may be nil when the response body is not needed.
+// Unsuccessful responses are returned as an *APIError.
+//
+// The returned response has its body closed, its headers can still be read.
+// Do can be used to call dwolla endpoints that this library does not wrap.
+func Do(ctx context.Context, c DwollaClient, method, hrefOrPath string, body, out interface{}) (*http.Response, error) {
+	req, err := NewRequest(ctx, c, method, hrefOrPath, body)
+	if err != nil {
+		return nil, err
+	}
+	return DoRequest(c, req, out)
+}
+
+// NewRequest creates a request to dwolla api with the headers dwolla expects.
+// See Do for the meaning of its arguments.
+func NewRequest(ctx context.Context, c DwollaClient, method, hrefOrPath string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		r = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshalling the json body")
+		}
+		r = bytes.NewReader(data)
+		contentType = halJSON
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ResolveURL(c, hrefOrPath), r)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the request")
+	}
+	req.Header.Set("Accept", halJSON)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// DoRequest sends req with c and decodes the JSON response into out.
+// See Do for details.
+func DoRequest(c DwollaClient, req *http.Request, out interface{}) (*http.Response, error) {
+	res, err := c.Send(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make request to dwolla api")
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res, NewAPIError(res)
+	}
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return res, nil
+	}
+	err = json.NewDecoder(res.Body).Decode(out)
+	if err != nil && err != io.EOF {
+		return res, errors.Wrap(err, "error parsing JSON response")
+	}
+	return res, nil
+}
+
+// dwollaHosts are the hosts of dwolla api found in the hrefs of its responses.
+var dwollaHosts = map[string]bool{
+	"api.dwolla.com":         true,
+	"api-sandbox.dwolla.com": true,
+}
+
+// ResolveURL returns hrefOrPath as an absolute url.
+// Paths are resolved against the root url of c. Hrefs of dwolla api, such as
+// the next link of a page, are rebased onto the root url of c with their path
+// and query, so that a root url set with WithRootURL is used for every request.
+func ResolveURL(c DwollaClient, hrefOrPath string) string {
+	if strings.HasPrefix(hrefOrPath, "https://") || strings.HasPrefix(hrefOrPath, "http://") {
+		u, err := url.Parse(hrefOrPath)
+		if err != nil || !dwollaHosts[u.Host] {
+			return hrefOrPath
+		}
+		hrefOrPath = u.RequestURI()
+	}
+	return strings.TrimSuffix(c.RootURL(), "/") + "/" + strings.TrimPrefix(hrefOrPath, "/")
+}
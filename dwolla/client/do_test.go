@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	var got map[string]string
+	var contentType, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		contentType = r.Header.Get("Content-Type")
+		accept = r.Header.Get("Accept")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/customers/abc")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": "abc"}`)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, DefaultRetryPolicy)
+	out := struct {
+		ID string `json:"id"`
+	}{}
+	res, err := Do(context.Background(), c, "POST", "customers", map[string]string{"firstName": "Jane"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != halJSON || accept != halJSON {
+		t.Errorf("expected HAL headers, got Content-Type %q and Accept %q", contentType, accept)
+	}
+	if got["firstName"] != "Jane" {
+		t.Errorf("expected the body to be sent as JSON, got %v", got)
+	}
+	if out.ID != "abc" || res.Header.Get("Location") == "" {
+		t.Errorf("expected the response to be decoded, got %+v", out)
+	}
+}
+
+func TestDoAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"code": "NotFound", "message": "The requested resource was not found."}`)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, DefaultRetryPolicy)
+	_, err := Do(context.Background(), c, "GET", ts.URL+"/customers/abc", nil, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	c := &Client{rootURL: "https://api-sandbox.dwolla.com/"}
+	for in, want := range map[string]string{
+		"/customers":                           "https://api-sandbox.dwolla.com/customers",
+		"customers":                            "https://api-sandbox.dwolla.com/customers",
+		"https://api.dwolla.com/customers/abc": "https://api-sandbox.dwolla.com/customers/abc",
+		"https://api-sandbox.dwolla.com/events?limit=25&offset=25": "https://api-sandbox.dwolla.com/events?limit=25&offset=25",
+		"http://localhost:8080/funding-sources/id":                 "http://localhost:8080/funding-sources/id",
+	} {
+		if got := ResolveURL(c, in); got != want {
+			t.Errorf("ResolveURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
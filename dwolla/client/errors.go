@@ -0,0 +1,127 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response body is read.
+const maxErrorBodySize = 1 << 20
+
+// Sentinel errors that an *APIError matches with errors.Is.
+var (
+	ErrBadRequest         = errors.New("dwolla: bad request")
+	ErrValidation         = errors.New("dwolla: validation error")
+	ErrDuplicateResource  = errors.New("dwolla: duplicate resource")
+	ErrInvalidAccessToken = errors.New("dwolla: invalid access token")
+	ErrForbidden          = errors.New("dwolla: forbidden")
+	ErrNotFound           = errors.New("dwolla: not found")
+	ErrTooManyRequests    = errors.New("dwolla: too many requests")
+	ErrServerError        = errors.New("dwolla: server error")
+)
+
+// ValidationError is one of the errors listed in the _embedded.errors
+// field of a dwolla validation error response.
+type ValidationError struct {
+	Links   map[string]Link `json:"_links"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Path    string          `json:"path"` // JSON pointer to the invalid field, e.g. /email
+}
+
+// APIError is returned for every non successful response of dwolla api.
+// It can be matched against the sentinel errors of this package with errors.Is,
+// and extracted from a wrapped error with errors.As.
+type APIError struct {
+	DwollaError
+	StatusCode int               // HTTP status code of the response
+	RequestID  string            // Value of the X-Request-Id response header
+	Errors     []ValidationError // Validation errors embedded in the response
+}
+
+// NewAPIError builds an *APIError from an unsuccessful response.
+// The body of the response is read but not closed.
+func NewAPIError(res *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get("X-Request-Id"),
+	}
+	body := struct {
+		DwollaError
+		Embedded struct {
+			Errors []ValidationError `json:"errors"`
+		} `json:"_embedded"`
+		OAuthError       string `json:"error"`
+		OAuthDescription string `json:"error_description"`
+	}{}
+	err := json.NewDecoder(io.LimitReader(res.Body, maxErrorBodySize)).Decode(&body)
+	if err == nil {
+		apiErr.DwollaError = body.DwollaError
+		apiErr.Errors = body.Embedded.Errors
+		if apiErr.Code == "" {
+			apiErr.Code = body.OAuthError
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = body.OAuthDescription
+		}
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxErrorBodySize))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(res.StatusCode)
+	}
+	return apiErr
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "dwolla: %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " %s", e.Code)
+	}
+	fmt.Fprintf(&b, ": %s", e.Message)
+	for _, v := range e.Errors {
+		fmt.Fprintf(&b, "; %s: %s", v.Path, v.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrValidation:
+		return e.Code == "ValidationError" || len(e.Errors) > 0
+	case ErrDuplicateResource:
+		if e.Code == "DuplicateResource" {
+			return true
+		}
+		for _, v := range e.Errors {
+			if v.Code == "Duplicate" {
+				return true
+			}
+		}
+		return false
+	case ErrInvalidAccessToken:
+		return e.StatusCode == http.StatusUnauthorized || e.Code == "InvalidAccessToken" || e.Code == "ExpiredAccessToken"
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+var mockValidationError = `
+{
+  "code": "ValidationError",
+  "message": "Validation error(s) present. See embedded errors list for more details.",
+  "_embedded": {
+    "errors": [
+      {
+        "code": "Duplicate",
+        "message": "A customer with the specified email already exists.",
+        "path": "/email",
+        "_links": {
+          "about": {
+            "href": "https://api-sandbox.dwolla.com/customers/707177c3-bf15-4e7e-b37c-55c3898d9bf4",
+            "type": "application/vnd.dwolla.v1.hal+json",
+            "resource-type": "customer"
+          }
+        }
+      }
+    ]
+  }
+}
+`
+
+func errorResponse(status int, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-Id", "0b2e3a9a-0e3f-4b1b-9d7e-2a1c5a1b1c1d")
+	rec.WriteHeader(status)
+	fmt.Fprint(rec, body)
+	return rec.Result()
+}
+
+func TestNewAPIErrorValidation(t *testing.T) {
+	err := pkgerrors.Wrap(NewAPIError(errorResponse(400, mockValidationError)), "failed to create customer")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected an *APIError")
+	}
+	if apiErr.StatusCode != 400 || apiErr.Code != "ValidationError" {
+		t.Errorf("unexpected status %d and code %s", apiErr.StatusCode, apiErr.Code)
+	}
+	if apiErr.RequestID != "0b2e3a9a-0e3f-4b1b-9d7e-2a1c5a1b1c1d" {
+		t.Errorf("unexpected request id %s", apiErr.RequestID)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Path != "/email" {
+		t.Errorf("unexpected validation errors %+v", apiErr.Errors)
+	}
+	for _, target := range []error{ErrBadRequest, ErrValidation, ErrDuplicateResource} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected error to match %v", target)
+		}
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("did not expect error to match ErrNotFound")
+	}
+}
+
+func TestNewAPIErrorNotFound(t *testing.T) {
+	err := NewAPIError(errorResponse(404, `{"code": "NotFound", "message": "The requested resource was not found."}`))
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("expected error to match ErrNotFound")
+	}
+	t.Log(err)
+}
+
+func TestNewAPIErrorEmptyBody(t *testing.T) {
+	err := NewAPIError(errorResponse(503, ""))
+	if !errors.Is(err, ErrServerError) {
+		t.Error("expected error to match ErrServerError")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Message != "Service Unavailable" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// idempotencyKeyTTL is how long dwolla remembers an Idempotency-Key.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// Idempotency holds the Idempotency-Key of a POST request and reports
+// back whether the key had already been sent by this client.
+//
+// KeyReused is only a local guess: dwolla does not say whether a response is
+// a replay, and a key sent with an attempt that failed before reaching dwolla
+// still counts as used. It is true whenever the response may be a replay.
+type Idempotency struct {
+	Key       string // Value of the Idempotency-Key header. Generated by the client when empty
+	KeyReused bool   // Set when this client had already sent the key in an earlier attempt or call
+}
+
+type idempotencyCtxKey struct{}
+
+// WithIdempotency returns a context that makes the POST requests made with it
+// send idem.Key as their Idempotency-Key. When idem.Key is empty a key is
+// generated and stored in idem, so that the call can be repeated with the same key.
+// The client keeps the key across its own retries and sets idem.KeyReused when
+// it had already sent a request with that key.
+//
+// Every call that creates a resource, such as customer.Create or
+// transfer.CreateTransfer, accepts such a context. Repeating the call with
+// the same key when it is not known whether the resource was created, for
+// instance after a timeout, creates it at most once.
+func WithIdempotency(ctx context.Context, idem *Idempotency) context.Context {
+	return context.WithValue(ctx, idempotencyCtxKey{}, idem)
+}
+
+// WithIdempotencyKey returns a context that makes the POST requests made with it
+// send key as their Idempotency-Key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return WithIdempotency(ctx, &Idempotency{Key: key})
+}
+
+// WithAutoIdempotencyKeys makes the client generate an Idempotency-Key for
+// every POST request that is not given one, so that POST requests are retried
+// according to the retry policy too.
+func WithAutoIdempotencyKeys() Option {
+	return func(o *options) {
+		o.autoIdempotency = true
+	}
+}
+
+// NewIdempotencyKey returns a random version 4 UUID to be used as an Idempotency-Key.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// idempotencyFromContext returns the Idempotency stored in ctx, if any.
+func idempotencyFromContext(ctx context.Context) *Idempotency {
+	idem, _ := ctx.Value(idempotencyCtxKey{}).(*Idempotency)
+	return idem
+}
+
+// markKeySent records that key was sent to dwolla and reports whether it had
+// already been sent within the time dwolla remembers it.
+func (c *Client) markKeySent(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.sentKeys == nil {
+		c.sentKeys = make(map[string]time.Time)
+	}
+	sentAt, ok := c.sentKeys[key]
+	seen := ok && now.Sub(sentAt) < idempotencyKeyTTL
+	if !seen {
+		c.sentKeys[key] = now
+	}
+	if now.Sub(c.keysPrunedAt) >= time.Minute {
+		for k, t := range c.sentKeys {
+			if now.Sub(t) >= idempotencyKeyTTL {
+				delete(c.sentKeys, k)
+			}
+		}
+		c.keysPrunedAt = now
+	}
+	return seen
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		n := len(keys)
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	idem := &Idempotency{}
+	ctx := WithIdempotency(context.Background(), idem)
+	req, err := http.NewRequestWithContext(ctx, "POST", ts.URL+"/transfers", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Send(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("expected the retried request to succeed, got %d", res.StatusCode)
+	}
+	if idem.Key == "" || len(keys) != 2 || keys[0] != idem.Key || keys[1] != idem.Key {
+		t.Errorf("expected key %q to be sent with both attempts, got %v", idem.Key, keys)
+	}
+	if !idem.KeyReused {
+		t.Error("expected the key to be reported as reused")
+	}
+}
+
+func TestSendExplicitIdempotencyKey(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		got = append(got, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, RetryPolicy{})
+	for i := 0; i < 2; i++ {
+		idem := &Idempotency{Key: "19a5a2e5-5b3f-4b6c-8a0b-0d5f5f6b8a41"}
+		ctx := WithIdempotency(context.Background(), idem)
+		req, err := http.NewRequestWithContext(ctx, "POST", ts.URL+"/customers", bytes.NewReader([]byte("{}")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := c.Send(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if idem.KeyReused != (i == 1) {
+			t.Errorf("call %d: unexpected key reused flag %v", i, idem.KeyReused)
+		}
+	}
+	if len(got) != 2 || got[0] != "19a5a2e5-5b3f-4b6c-8a0b-0d5f5f6b8a41" {
+		t.Errorf("unexpected keys %v", got)
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	a, b := NewIdempotencyKey(), NewIdempotencyKey()
+	if len(a) != 36 || a == b {
+		t.Errorf("unexpected keys %q and %q", a, b)
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by CreateClient.
+type Option func(*options)
+
+type options struct {
+	httpClient      *http.Client
+	transport       http.RoundTripper
+	rootURL         string
+	userAgent       string
+	timeout         time.Duration
+	eagerDiscover   bool
+	retryPolicy     *RetryPolicy
+	autoIdempotency bool
+}
+
+// WithHTTPClient makes the client send its requests with hc instead of a zero-value http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = hc
+	}
+}
+
+// WithTransport makes the client send its requests through rt.
+// It replaces the transport of the http.Client given to WithHTTPClient, if any.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// WithRootURL overrides the dwolla api root url that is otherwise chosen by env.
+// It can point the client at a local stand-in of dwolla api or an egress proxy.
+func WithRootURL(URL string) Option {
+	return func(o *options) {
+		o.rootURL = URL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the default time limit of every request made by the client.
+// A deadline on the request context still applies when it is shorter.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithEagerDiscovery makes CreateClient call the root endpoint right away,
+// so that invalid credentials are reported by CreateClient itself.
+// By default the root links are fetched on the first call to Links.
+func WithEagerDiscovery() Option {
+	return func(o *options) {
+		o.eagerDiscover = true
+	}
+}
+
+// buildHTTPClient returns the http.Client described by the options.
+// A given http.Client is copied so that the caller's value is never modified.
+func (o *options) buildHTTPClient() *http.Client {
+	hc := &http.Client{}
+	if o.httpClient != nil {
+		*hc = *o.httpClient
+	}
+	if o.transport != nil {
+		hc.Transport = o.transport
+	}
+	if o.timeout > 0 {
+		hc.Timeout = o.timeout
+	}
+	return hc
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	count int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCreateClientOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "dwolla-go-test" {
+			t.Errorf("unexpected user agent %q", r.Header.Get("User-Agent"))
+		}
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		fmt.Fprint(w, mockRoot)
+	}))
+	defer ts.Close()
+	rt := &countingTransport{}
+	c, err := CreateClient("sandbox", "123456789", "123456789",
+		WithRootURL(ts.URL),
+		WithTransport(rt),
+		WithUserAgent("dwolla-go-test"),
+		WithTimeout(time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RootURL() != ts.URL {
+		t.Errorf("expected root url %s, got %s", ts.URL, c.RootURL())
+	}
+	if n := atomic.LoadInt32(&rt.count); n != 0 {
+		t.Errorf("expected no requests before first use, got %d", n)
+	}
+	links, err := c.Links(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if links["customers"]["href"] == "" {
+		t.Error("expected the customers link to be discovered")
+	}
+	if n := atomic.LoadInt32(&rt.count); n != 2 {
+		t.Errorf("expected a token and a root request, got %d requests", n)
+	}
+}
+
+func TestWithHTTPClientNotModified(t *testing.T) {
+	hc := &http.Client{}
+	o := &options{}
+	WithHTTPClient(hc)(o)
+	WithTimeout(time.Second)(o)
+	built := o.buildHTTPClient()
+	if built.Timeout != time.Second {
+		t.Errorf("expected timeout of 1s, got %s", built.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Error("expected the given http.Client to be left unchanged")
+	}
+}
+
+func TestCreateClientEagerDiscovery(t *testing.T) {
+	rt := &countingTransport{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		fmt.Fprint(w, mockRoot)
+	}))
+	defer ts.Close()
+	_, err := CreateClient("sandbox", "123456789", "123456789",
+		WithRootURL(ts.URL),
+		WithTransport(rt),
+		WithEagerDiscovery(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&rt.count); n != 2 {
+		t.Errorf("expected a token and a root request, got %d requests", n)
+	}
+}
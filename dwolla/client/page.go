@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOptions selects a page of a list endpoint.
+type ListOptions struct {
+	Limit  int // Number of items per page. Dwolla uses 25 when zero, and allows up to 200
+	Offset int // Number of items to skip
+}
+
+// Values returns the query parameters of the options.
+// It is safe to call on a nil *ListOptions.
+func (o *ListOptions) Values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		v.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return v
+}
+
+// WithQuery returns hrefOrPath with the parameters of q added to its query string.
+func WithQuery(hrefOrPath string, q url.Values) string {
+	if len(q) == 0 {
+		return hrefOrPath
+	}
+	sep := "?"
+	if strings.Contains(hrefOrPath, "?") {
+		sep = "&"
+	}
+	return hrefOrPath + sep + q.Encode()
+}
+
+// Page holds the pagination fields shared by every dwolla list response.
+type Page struct {
+	Links map[string]Link `json:"_links"`
+	Total int             `json:"total"` // Number of items in the whole list
+}
+
+// NextHref returns the href of the next page, or an empty string on the last page.
+func (p Page) NextHref() string {
+	return p.Links["next"].Href
+}
+
+// PageFunc fetches the page at href into the caller's buffer
+// and returns its pagination fields and number of items.
+type PageFunc func(ctx context.Context, href string) (Page, int, error)
+
+// PageIterator walks the items of a list endpoint page by page,
+// following the next link of each page until the list is exhausted.
+// The typed iterators of the other packages are built on it.
+type PageIterator struct {
+	ctx   context.Context
+	fetch PageFunc
+	href  string // Href of the next page to fetch, empty once the last page was fetched
+	page  Page
+	n     int // Number of items in the current page
+	i     int // Index of the current item in the current page
+	err   error
+}
+
+// NewPageIterator returns an iterator that starts at the page at href.
+func NewPageIterator(ctx context.Context, href string, fetch PageFunc) *PageIterator {
+	return &PageIterator{ctx: ctx, fetch: fetch, href: href, i: -1}
+}
+
+// Next advances to the next item, fetching the next page when needed.
+// It returns false when the list is exhausted or a request failed.
+func (it *PageIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.i++
+	for it.i >= it.n {
+		if it.href == "" {
+			return false
+		}
+		page, n, err := it.fetch(it.ctx, it.href)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.n, it.i = page, n, 0
+		it.href = page.NextHref()
+	}
+	return true
+}
+
+// Index returns the index of the current item in the current page.
+func (it *PageIterator) Index() int {
+	return it.i
+}
+
+// Total returns the number of items in the whole list, as reported
+// by the last fetched page.
+func (it *PageIterator) Total() int {
+	return it.page.Total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *PageIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithQuery(t *testing.T) {
+	opts := &ListOptions{Limit: 200, Offset: 400}
+	if got := WithQuery("/customers", opts.Values()); got != "/customers?limit=200&offset=400" {
+		t.Errorf("unexpected path %q", got)
+	}
+	if got := WithQuery("/customers?search=jane", opts.Values()); got != "/customers?search=jane&limit=200&offset=400" {
+		t.Errorf("unexpected path %q", got)
+	}
+	var none *ListOptions
+	if got := WithQuery("/customers", none.Values()); got != "/customers" {
+		t.Errorf("expected nil options to leave the path unchanged, got %q", got)
+	}
+}
+
+func TestPageIteratorRootURL(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		requests = append(requests, r.URL.RequestURI())
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"_links": {"next": {"href": "https://api.dwolla.com/events?limit=1&offset=1"}}, "_embedded": {"events": [{"id": "1"}]}, "total": 2}`)
+			return
+		}
+		fmt.Fprint(w, `{"_links": {}, "_embedded": {"events": [{"id": "2"}]}, "total": 2}`)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, DefaultRetryPolicy)
+	var ids []string
+	it := NewPageIterator(context.Background(), "/events?limit=1", func(ctx context.Context, href string) (Page, int, error) {
+		body := struct {
+			Page
+			Embedded map[string][]struct {
+				ID string `json:"id"`
+			} `json:"_embedded"`
+		}{}
+		_, err := Do(ctx, c, "GET", href, nil, &body)
+		for _, evt := range body.Embedded["events"] {
+			ids = append(ids, evt.ID)
+		}
+		return body.Page, len(body.Embedded["events"]), err
+	})
+	for it.Next() {
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 2]" || fmt.Sprint(requests) != "[/events?limit=1 /events?limit=1&offset=1]" {
+		t.Errorf("expected every page to be fetched from the root url, got %v from %v", ids, requests)
+	}
+}
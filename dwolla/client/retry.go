@@ -0,0 +1,109 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries requests that failed with
+// a 429 Too Many Requests, a 5xx status code or a network error.
+//
+// A Retry-After header sent by dwolla is honoured as long as it does not
+// exceed MaxBackoff. When it asks for a longer wait the failed response is
+// returned to the caller instead of blocking it.
+//
+// Only GET, HEAD and OPTIONS requests are retried, unless the request carries
+// an Idempotency-Key header that makes it safe for dwolla to receive it twice.
+type RetryPolicy struct {
+	MaxAttempts int              // Attempts made in total, including the first one. Values below 2 disable retries
+	MinBackoff  time.Duration    // Delay before the first retry
+	MaxBackoff  time.Duration    // Upper bound of the delay between two attempts
+	OnRetry     func(RetryEvent) // Called before waiting for each retry, if set
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	Request    *http.Request // Request that failed
+	Attempt    int           // Number of the failed attempt, starting at 1
+	Delay      time.Duration // Time to wait before the next attempt
+	StatusCode int           // Status code of the failed attempt, 0 on a network error
+	Err        error         // Network error of the failed attempt, if any
+}
+
+// DefaultRetryPolicy is the retry policy of clients created by CreateClient
+// without the WithRetryPolicy option.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+}
+
+// WithRetryPolicy sets the retry policy of the client.
+// Pass a zero RetryPolicy to disable retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &p
+	}
+}
+
+// retryable reports whether the outcome of an attempt of req may be retried.
+func retryable(req *http.Request, res *http.Response, err error) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if req.Header.Get("Idempotency-Key") == "" {
+			return false
+		}
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the attempt following the given one.
+// A Retry-After header sent with res takes precedence over the exponential backoff.
+// It reports false when Retry-After asks for a longer wait than MaxBackoff,
+// in which case the request should not be retried.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) (time.Duration, bool) {
+	if res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return d, p.MaxBackoff <= 0 || d <= p.MaxBackoff
+		}
+	}
+	d := p.MinBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0, true
+	}
+	// Equal jitter: keep half of the delay and randomize the other half.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)), true
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func retryServer(failures int32, status int) (*httptest.Server, *int32) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, mockRoot)
+	}))
+	return ts, &requests
+}
+
+func retryClient(URL string, p RetryPolicy) *Client {
+	c := &Client{
+		Env:          "Test",
+		ClientID:     "123456789",
+		ClientSecret: "123456789",
+		retryPolicy:  p,
+	}
+	c.SetRootURL(URL)
+	return c
+}
+
+func TestSendRetriesGet(t *testing.T) {
+	ts, requests := retryServer(2, http.StatusServiceUnavailable)
+	defer ts.Close()
+	var events []RetryEvent
+	c := retryClient(ts.URL, RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+		OnRetry: func(e RetryEvent) {
+			events = append(events, e)
+		},
+	})
+	_, err := c.Root(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+	if len(events) != 2 || events[1].Attempt != 2 || events[1].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("unexpected retry events %+v", events)
+	}
+}
+
+func TestSendGivesUp(t *testing.T) {
+	ts, requests := retryServer(5, http.StatusTooManyRequests)
+	defer ts.Close()
+	c := retryClient(ts.URL, RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond})
+	_, err := c.Root(context.Background())
+	if err == nil {
+		t.Error("expected an error after the last attempt")
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestSendDoesNotRetryPost(t *testing.T) {
+	ts, requests := retryServer(1, http.StatusInternalServerError)
+	defer ts.Close()
+	c := retryClient(ts.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	req, err := http.NewRequest("POST", ts.URL+"/transfers", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Send(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusInternalServerError || *requests != 1 {
+		t.Errorf("expected a single failed request, got %d requests", *requests)
+	}
+
+	req, err = http.NewRequest("POST", ts.URL+"/transfers", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Idempotency-Key", "c4b5b6f4-7d7c-4b9e-9d2b-6f6b8e3b7a01")
+	res, err = c.Send(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected request with an idempotency key to succeed, got %d", res.StatusCode)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 1; attempt < 8; attempt++ {
+		d, ok := p.backoff(attempt, nil)
+		if !ok || d < 50*time.Millisecond || d > time.Second {
+			t.Errorf("backoff of attempt %d out of range: %s", attempt, d)
+		}
+	}
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	if d, ok := p.backoff(1, res); !ok || d != time.Second {
+		t.Errorf("expected Retry-After to be honoured, got %s", d)
+	}
+	res = &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if _, ok := p.backoff(1, res); ok {
+		t.Error("expected a Retry-After longer than MaxBackoff to stop retries")
+	}
+}
+
+func TestSendLongRetryAfter(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, mockToken)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+	c := retryClient(ts.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Second})
+	start := time.Now()
+	_, err := c.Root(context.Background())
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("expected a too many requests error, got %v", err)
+	}
+	if requests != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected a single request without waiting, got %d requests in %s", requests, time.Since(start))
+	}
+}
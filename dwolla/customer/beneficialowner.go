@@ -0,0 +1,178 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
+
+// Verification statuses of a beneficial owner.
+const (
+	OwnerVerified   = "verified"
+	OwnerDocument   = "document"
+	OwnerIncomplete = "incomplete"
+)
+
+// Certification statuses of the beneficial ownership of a customer.
+const (
+	OwnershipUncertified = "uncertified"
+	OwnershipCertified   = "certified"
+	OwnershipRecertify   = "recertify"
+)
+
+// BeneficialOwner is an individual who owns 25% or more of
+// a business verified customer.
+type BeneficialOwner struct {
+	Client             client.DwollaClient    `json:"-"`
+	Links              map[string]client.Link `json:"_links"`
+	ID                 string                 `json:"id"`
+	FirstName          string                 `json:"firstName"`
+	LastName           string                 `json:"lastName"`
+	DateOfBirth        string                 `json:"dateOfBirth,omitempty"`
+	SSN                string                 `json:"ssn,omitempty"`
+	Address            *Address               `json:"address,omitempty"`
+	Passport           *Passport              `json:"passport,omitempty"`
+	VerificationStatus string                 `json:"verificationStatus"`
+	CreatedAt          time.Time              `json:"created"`
+}
+
+// Address is the address of a beneficial owner or a controller.
+// Addresses outside of the United States leave PostalCode empty when
+// the country has no postal codes.
+type Address struct {
+	Address1            string `json:"address1"`
+	Address2            string `json:"address2,omitempty"`
+	Address3            string `json:"address3,omitempty"`
+	City                string `json:"city"`
+	StateProvinceRegion string `json:"stateProvinceRegion"`
+	PostalCode          string `json:"postalCode,omitempty"`
+	Country             string `json:"country"`
+}
+
+// Passport identifies a beneficial owner or a controller without an SSN.
+type Passport struct {
+	Number  string `json:"number"`
+	Country string `json:"country"`
+}
+
+// BeneficialOwnership is the certification of the beneficial owners of a customer.
+type BeneficialOwnership struct {
+	Links  map[string]client.Link `json:"_links"`
+	Status string                 `json:"status"`
+}
+
+type listBeneficialOwnersResponse struct {
+	client.Page
+	Embedded map[string][]BeneficialOwner `json:"_embedded"`
+}
+
+// beneficialOwnerRequest is the body sent to create or update a beneficial owner.
+type beneficialOwnerRequest struct {
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	DateOfBirth string    `json:"dateOfBirth,omitempty"`
+	SSN         string    `json:"ssn,omitempty"`
+	Address     *Address  `json:"address,omitempty"`
+	Passport    *Passport `json:"passport,omitempty"`
+}
+
+func newBeneficialOwnerRequest(o *BeneficialOwner) *beneficialOwnerRequest {
+	return &beneficialOwnerRequest{
+		FirstName:   o.FirstName,
+		LastName:    o.LastName,
+		DateOfBirth: o.DateOfBirth,
+		SSN:         o.SSN,
+		Address:     o.Address,
+		Passport:    o.Passport,
+	}
+}
+
+// CreateBeneficialOwner adds a beneficial owner to the customer
+// and returns the ID and href of the new beneficial owner.
+func (cu *Customer) CreateBeneficialOwner(ctx context.Context, o *BeneficialOwner) (*client.Created, error) {
+	return client.Create(ctx, cu.Client, "/customers/"+cu.ID+"/beneficial-owners", newBeneficialOwnerRequest(o))
+}
+
+// CreateAndGetBeneficialOwner adds a beneficial owner like CreateBeneficialOwner,
+// then retrieves the created beneficial owner.
+func (cu *Customer) CreateAndGetBeneficialOwner(ctx context.Context, o *BeneficialOwner) (*BeneficialOwner, error) {
+	created, err := cu.CreateBeneficialOwner(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	return GetBeneficialOwner(ctx, cu.Client, created.ID)
+}
+
+// ListBeneficialOwners retrieves the beneficial owners of the customer.
+// It also returns the number of beneficial owners.
+func (cu *Customer) ListBeneficialOwners(ctx context.Context) ([]BeneficialOwner, int, error) {
+	body := &listBeneficialOwnersResponse{}
+	_, err := client.Do(ctx, cu.Client, "GET", "/customers/"+cu.ID+"/beneficial-owners", nil, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	owners := body.Embedded["beneficial-owners"]
+	for i := range owners {
+		owners[i].Client = cu.Client
+	}
+	return owners, body.Total, nil
+}
+
+// GetBeneficialOwner retrieves a beneficial owner by ID.
+func GetBeneficialOwner(ctx context.Context, c client.DwollaClient, ownerID string) (*BeneficialOwner, error) {
+	body := &BeneficialOwner{}
+	_, err := client.Do(ctx, c, "GET", "/beneficial-owners/"+ownerID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	body.Client = c
+	return body, nil
+}
+
+// GetBeneficialOwnership retrieves the certification status of the
+// beneficial owners of the customer.
+func (cu *Customer) GetBeneficialOwnership(ctx context.Context) (*BeneficialOwnership, error) {
+	body := &BeneficialOwnership{}
+	_, err := client.Do(ctx, cu.Client, "GET", "/customers/"+cu.ID+"/beneficial-ownership", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// CertifyBeneficialOwnership certifies that the beneficial owners of the customer
+// are complete and correct. A business verified customer can't send funds
+// until its beneficial ownership is certified.
+func (cu *Customer) CertifyBeneficialOwnership(ctx context.Context) (*BeneficialOwnership, error) {
+	req := &statusRequest{Status: OwnershipCertified}
+	body := &BeneficialOwnership{}
+	_, err := client.Do(ctx, cu.Client, "POST", "/customers/"+cu.ID+"/beneficial-ownership", req, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Update sends the name, date of birth, SSN, address and passport of the
+// beneficial owner to dwolla, such as to retry its verification.
+func (o *BeneficialOwner) Update(ctx context.Context) error {
+	_, err := client.Do(ctx, o.Client, "POST", "/beneficial-owners/"+o.ID, newBeneficialOwnerRequest(o), nil)
+	return err
+}
+
+// Remove the beneficial owner from its customer.
+func (o *BeneficialOwner) Remove(ctx context.Context) error {
+	_, err := client.Do(ctx, o.Client, "DELETE", "/beneficial-owners/"+o.ID, nil, nil)
+	return err
+}
+
+// ListDocuments retrieves a page of the documents submitted for the beneficial owner.
+// It also returns the number of documents in the whole list.
+func (o *BeneficialOwner) ListDocuments(ctx context.Context, opts *client.ListOptions) ([]Document, int, error) {
+	docs, page, err := listDocumentsPage(ctx, o.Client, client.WithQuery("/beneficial-owners/"+o.ID+"/documents", opts.Values()))
+	if err != nil {
+		return nil, 0, err
+	}
+	return docs, page.Total, nil
+}
@@ -0,0 +1,151 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var mockBeneficialOwner = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8"
+    }
+  },
+  "id": "07d59716-ef22-4fe6-98e8-f3190233dfb8",
+  "firstName": "document",
+  "lastName": "owner",
+  "address": {
+    "address1": "Calle 456",
+    "address2": "Apt 3",
+    "city": "Lima",
+    "stateProvinceRegion": "Lima",
+    "country": "PE"
+  },
+  "verificationStatus": "verified",
+  "created": "2018-05-10T19:59:22.643Z"
+}
+`
+
+var mockBeneficialOwners = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/customers/FC451A7A-AE30-4404-AB95-E3553FCD733F/beneficial-owners"
+    }
+  },
+  "_embedded": {
+    "beneficial-owners": [` + mockBeneficialOwner + `]
+  },
+  "total": 1
+}
+`
+
+func TestCreateBeneficialOwner(t *testing.T) {
+	var got map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/customers/FC451A7A-AE30-4404-AB95-E3553FCD733F/beneficial-owners" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8")
+		w.WriteHeader(201)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
+	owner := &BeneficialOwner{
+		FirstName:   "document",
+		LastName:    "owner",
+		DateOfBirth: "1990-11-11",
+		Address:     &Address{Address1: "Calle 456", City: "Lima", StateProvinceRegion: "Lima", Country: "PE"},
+		Passport:    &Passport{Number: "JKL897S", Country: "PE"},
+	}
+	created, err := cu.CreateBeneficialOwner(context.Background(), owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "07d59716-ef22-4fe6-98e8-f3190233dfb8" {
+		t.Errorf("unexpected beneficial owner id %q", created.ID)
+	}
+	if got["passport"] == nil || got["address"] == nil || got["created"] != nil || got["id"] != nil {
+		t.Errorf("unexpected request body %v", got)
+	}
+}
+
+func TestListBeneficialOwners(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockBeneficialOwners)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
+	owners, total, err := cu.ListBeneficialOwners(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(owners) != 1 || owners[0].Client == nil {
+		t.Fatalf("expected a beneficial owner with a client, got %+v", owners)
+	}
+	if owners[0].Address.Country != "PE" || owners[0].VerificationStatus != OwnerVerified {
+		t.Errorf("unexpected beneficial owner %+v", owners[0])
+	}
+}
+
+func TestUpdateAndRemoveBeneficialOwner(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, mockBeneficialOwner)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	owner, err := GetBeneficialOwner(context.Background(), mock, "07d59716-ef22-4fe6-98e8-f3190233dfb8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner.LastName = "owner2"
+	err = owner.Update(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = owner.Remove(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "GET /beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8," +
+		"POST /beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8," +
+		"DELETE /beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8"
+	if got := strings.Join(requests, ","); got != want {
+		t.Errorf("unexpected requests %s", got)
+	}
+}
+
+func TestCertifyBeneficialOwnership(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got = string(body)
+		fmt.Fprint(w, `{"_links": {}, "status": "certified"}`)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
+	ownership, err := cu.CertifyBeneficialOwnership(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `{"status":"certified"}` || ownership.Status != OwnershipCertified {
+		t.Errorf("unexpected certification %+v, sent %s", ownership, got)
+	}
+}
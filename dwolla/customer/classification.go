@@ -0,0 +1,79 @@
+package customer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
+
+// BusinessClassification is a category of businesses. The ID of one of its
+// industry classifications is the businessClassification of a business
+// verified customer.
+type BusinessClassification struct {
+	Links    map[string]client.Link              `json:"_links"`
+	Embedded map[string][]IndustryClassification `json:"_embedded"`
+	ID       string                              `json:"id"`
+	Name     string                              `json:"name"`
+}
+
+// IndustryClassification is an industry within a business classification.
+type IndustryClassification struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type listBusinessClassificationsResponse struct {
+	client.Page
+	Embedded map[string][]BusinessClassification `json:"_embedded"`
+}
+
+// ListBusinessClassifications retrieves the business classifications with their
+// industry classifications. It also returns the number of business classifications.
+func ListBusinessClassifications(ctx context.Context, c client.DwollaClient) ([]BusinessClassification, int, error) {
+	body := &listBusinessClassificationsResponse{}
+	_, err := client.Do(ctx, c, "GET", "/business-classifications", nil, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body.Embedded["business-classifications"], body.Total, nil
+}
+
+// GetBusinessClassification retrieves a business classification by ID.
+func GetBusinessClassification(ctx context.Context, c client.DwollaClient, classificationID string) (*BusinessClassification, error) {
+	body := &BusinessClassification{}
+	_, err := client.Do(ctx, c, "GET", "/business-classifications/"+classificationID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Industries returns the industry classifications of the business classification.
+func (b *BusinessClassification) Industries() []IndustryClassification {
+	return b.Embedded["industry-classifications"]
+}
+
+// FindIndustryClassifications returns the industry classifications of bcs
+// whose name contains every word of keyword, ignoring case.
+// An empty keyword matches every industry classification.
+func FindIndustryClassifications(bcs []BusinessClassification, keyword string) []IndustryClassification {
+	words := strings.Fields(strings.ToLower(keyword))
+	var found []IndustryClassification
+	for i := range bcs {
+		for _, ic := range bcs[i].Industries() {
+			name := strings.ToLower(ic.Name)
+			match := true
+			for _, w := range words {
+				if !strings.Contains(name, w) {
+					match = false
+					break
+				}
+			}
+			if match {
+				found = append(found, ic)
+			}
+		}
+	}
+	return found
+}
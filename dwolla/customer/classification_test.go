@@ -0,0 +1,107 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var mockBusinessClassifications = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/business-classifications"
+    }
+  },
+  "_embedded": {
+    "business-classifications": [
+      {
+        "_links": {
+          "self": {
+            "href": "https://api-sandbox.dwolla.com/business-classifications/9ed3f669-7d6f-11e6-a2f3-7a5c5f53c7b2"
+          }
+        },
+        "_embedded": {
+          "industry-classifications": [
+            {
+              "id": "9ed3f67c-7d6f-11e6-a2f3-7a5c5f53c7b2",
+              "name": "Gambling"
+            },
+            {
+              "id": "9ed3f67a-7d6f-11e6-a2f3-7a5c5f53c7b2",
+              "name": "Video tape rental stores"
+            }
+          ]
+        },
+        "id": "9ed3f669-7d6f-11e6-a2f3-7a5c5f53c7b2",
+        "name": "Entertainment and media"
+      },
+      {
+        "_links": {
+          "self": {
+            "href": "https://api-sandbox.dwolla.com/business-classifications/9ed35a3b-7d6f-11e6-a2f3-7a5c5f53c7b2"
+          }
+        },
+        "_embedded": {
+          "industry-classifications": [
+            {
+              "id": "9ed35a4e-7d6f-11e6-a2f3-7a5c5f53c7b2",
+              "name": "Computer software stores"
+            }
+          ]
+        },
+        "id": "9ed35a3b-7d6f-11e6-a2f3-7a5c5f53c7b2",
+        "name": "Computer software"
+      }
+    ]
+  },
+  "total": 2
+}
+`
+
+func TestListBusinessClassifications(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockBusinessClassifications)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	bcs, total, err := ListBusinessClassifications(context.Background(), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(bcs) != 2 || len(bcs[0].Industries()) != 2 {
+		t.Errorf("unexpected business classifications %+v", bcs)
+	}
+}
+
+func TestFindIndustryClassifications(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockBusinessClassifications)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	bcs, _, err := ListBusinessClassifications(context.Background(), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		keyword string
+		want    int
+	}{
+		{"gambling", 1},
+		{"STORES", 2},
+		{"software stores", 1},
+		{"stores tape", 1},
+		{"bakery", 0},
+		{"", 3},
+	}
+	for _, tt := range tests {
+		if got := FindIndustryClassifications(bcs, tt.keyword); len(got) != tt.want {
+			t.Errorf("%q: expected %d industry classifications, got %+v", tt.keyword, tt.want, got)
+		}
+	}
+}
@@ -2,60 +2,77 @@
 package customer
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"mime/multipart"
-	"net/http"
-	"os"
-	"strings"
+	"context"
+	"net/url"
 
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/transfer"
-	"github.com/pkg/errors"
 )
 
 // Customer represents an individual or business with whom you intend to transact with
 type Customer struct {
-	Client       client.DwollaClient
-	ID           string                 `json:"id"`
-	FirstName    string                 `json:"firstName"`
-	LastName     string                 `json:"lastName"`
-	Email        string                 `json:"email"`
-	Type         string                 `json:"type"`
-	Status       string                 `json:"status"`
-	BusinessName string                 `json:"businessName,omitempty"`
-	IPAddress    string                 `json:"ipAddress"`
-	CreatedAt    string                 `json:"created"`
-	DateOfBirth  string                 `json:"dateOfBirth,omitempty"`
-	SSN          string                 `json:"ssn,omitempty"`
-	State        string                 `json:"state"`
-	PostalCode   string                 `json:"postalCode"`
-	City         string                 `json:"city"`
-	Address      string                 `json:"address1,omitempty"`
-	Passport     string                 `json:"passport,omitempty"`
-	Links        map[string]client.Link `json:"_links"`
+	Client                 client.DwollaClient    `json:"-"`
+	ID                     string                 `json:"id"`
+	FirstName              string                 `json:"firstName"`
+	LastName               string                 `json:"lastName"`
+	Email                  string                 `json:"email"`
+	Type                   string                 `json:"type"`
+	Status                 Status                 `json:"status"`
+	BusinessName           string                 `json:"businessName,omitempty"`
+	IPAddress              string                 `json:"ipAddress"`
+	CreatedAt              string                 `json:"created"`
+	DateOfBirth            string                 `json:"dateOfBirth,omitempty"`
+	SSN                    string                 `json:"ssn,omitempty"`
+	State                  string                 `json:"state"`
+	PostalCode             string                 `json:"postalCode"`
+	City                   string                 `json:"city"`
+	Address                string                 `json:"address1,omitempty"`
+	Address2               string                 `json:"address2,omitempty"`
+	Phone                  string                 `json:"phone,omitempty"`
+	Passport               string                 `json:"passport,omitempty"`
+	CorrelationID          string                 `json:"correlationId,omitempty"`
+	BusinessType           string                 `json:"businessType,omitempty"`
+	BusinessClassification string                 `json:"businessClassification,omitempty"`
+	DoingBusinessAs        string                 `json:"doingBusinessAs,omitempty"`
+	Website                string                 `json:"website,omitempty"`
+	Controller             *Controller            `json:"controller,omitempty"`
+	Links                  map[string]client.Link `json:"_links"`
 }
 
 // Document is a file sumbitted to dwolla to be validated
 type Document struct {
-	Client    client.DwollaClient
-	Links     map[string]client.Link `json:"_links"`
-	ID        string                 `json:"id"`
-	Status    string                 `json:"status"`
-	Type      string                 `json:"passport"`
-	CreatedAt string                 `json:"created"`
+	Client        client.DwollaClient    `json:"-"`
+	Links         map[string]client.Link `json:"_links"`
+	ID            string                 `json:"id"`
+	Status        string                 `json:"status"`
+	Type          string                 `json:"type"`
+	CreatedAt     string                 `json:"created"`
+	FailureReason string                 `json:"failureReason,omitempty"`
 }
 
 type listCustomersResponse struct {
-	Links    map[string]client.Link `json:"_links"`
-	Embedded map[string][]Customer  `json:"_embedded"`
+	client.Page
+	Embedded map[string][]Customer `json:"_embedded"`
 }
 
 type listDocumentsResponse struct {
-	Links    map[string]client.Link `json:"_links"`
-	Embedded map[string][]Document  `json:"_embedded"`
+	client.Page
+	Embedded map[string][]Document `json:"_embedded"`
+}
+
+// Iterator iterates over a list of customers,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []Customer
+}
+
+// DocumentIterator iterates over a list of documents,
+// following the next link of each page.
+type DocumentIterator struct {
+	pages *client.PageIterator
+	items []Document
 }
 
 type createFudingSourceToken struct {
@@ -63,474 +80,270 @@ type createFudingSourceToken struct {
 	Links map[string]map[string]string `json:"_links"`
 }
 
-// Create a new customer
-func Create(c client.DwollaClient, cu *Customer) (string, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+// Create a new customer of the type of req and return the ID and href of
+// the new customer. req is validated first, a *ValidationError is returned
+// without calling dwolla when required fields are missing.
+func Create(ctx context.Context, c client.DwollaClient, req CreateRequest) (*client.Created, error) {
+	err := req.Validate()
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(cu)
-	if err != nil {
-		return "", errors.Wrap(err, "error marshalling customer into req body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers", bytes.NewReader(body))
-	if err != nil {
-		return "", errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return strings.TrimPrefix(res.Header.Get("Location"), c.RootURL()+"/customers/"), nil
-	case 403:
-		return "", errors.New("not authorized to create customers")
-	case 400:
-		io.Copy(os.Stdout, res.Body)
-		return "", errors.New("duplicate customer or validation error")
-	case 404:
-		return "", errors.New("account not found")
-	default:
-		return "", errors.New(res.Status)
+		return nil, err
 	}
+	return client.Create(ctx, c, "/customers", req)
 }
 
-// List retrieves a list of created customers
-func List(c client.DwollaClient) ([]Customer, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/customers", nil)
+// CreateAndGet creates a new customer like Create, then retrieves the created customer.
+func CreateAndGet(ctx context.Context, c client.DwollaClient, req CreateRequest) (*Customer, error) {
+	created, err := Create(ctx, c, req)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &listCustomersResponse{}
-		err = d.Decode(body)
-		for _, customer := range body.Embedded["customers"] {
-			customer.Client = c
-		}
-		return body.Embedded["customers"], nil
-	case 403:
-		return nil, errors.New("not authorized to list customers")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	return GetCustomer(ctx, c, created.ID)
 }
 
-// GetCustomer retrieves a customer belonging to the authorized Dwolla Master Account by it's ID
-func GetCustomer(c client.DwollaClient, customerID string) (*Customer, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
+// ListOptions filters the customers listed by List and Iter
+// and selects a page of the list.
+type ListOptions struct {
+	Search string   // Matched against the names, email and business name of customers
+	Email  string   // Exact email of the customers
+	Status []Status // Statuses of the customers, any of them matches
+	Limit  int      // Number of customers per page. Dwolla uses 25 when zero, and allows up to 200
+	Offset int      // Number of customers to skip
+}
+
+// Values returns the query parameters of the options.
+// It is safe to call on a nil *ListOptions.
+func (o *ListOptions) Values() url.Values {
+	if o == nil {
+		return url.Values{}
 	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/customers/"+customerID, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
+	v := (&client.ListOptions{Limit: o.Limit, Offset: o.Offset}).Values()
+	if o.Search != "" {
+		v.Set("search", o.Search)
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
+	if o.Email != "" {
+		v.Set("email", o.Email)
 	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Customer{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		body.Client = c
-		return body, nil
-	case 403:
-		return nil, errors.New("not authorized to retrieve the customer")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+	for _, status := range o.Status {
+		v.Add("status", string(status))
 	}
+	return v
 }
 
-// Update can be used to achieve the following :
-// update Customer information,
-// upgrade an unverified Customer to a verified Customer,
-// suspend a Customer, deactivate a Customer,
-// reactivate a Customer,
-// and update a verified Customer’s information to retry verification.
-func (cu *Customer) Update() error {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(cu)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling customer into req body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers/"+cu.ID, bytes.NewReader(body))
+// List retrieves a page of created customers matching opts.
+// It also returns the number of matching customers in the whole list.
+func List(ctx context.Context, c client.DwollaClient, opts *ListOptions) ([]Customer, int, error) {
+	customers, page, err := listPage(ctx, c, client.WithQuery("/customers", opts.Values()))
 	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		return nil
-	case 403:
-		return errors.New("not authorized to update the customer")
-	case 404:
-		return errors.New("account not found")
-	default:
-		return errors.New(res.Status)
+		return nil, 0, err
 	}
+	return customers, page.Total, nil
+}
 
+// Iter returns an iterator over all created customers matching opts.
+// opts also selects the size of the pages and where the iteration starts.
+func Iter(ctx context.Context, c client.DwollaClient, opts *ListOptions) *Iterator {
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, client.WithQuery("/customers", opts.Values()), func(ctx context.Context, href string) (client.Page, int, error) {
+		customers, page, err := listPage(ctx, c, href)
+		it.items = customers
+		return page, len(customers), err
+	})
+	return it
 }
 
-// TODO : Add ListBusinessClassification Method
+// Next advances to the next customer. It returns false when
+// there are no more customers or a request failed.
+func (it *Iterator) Next() bool {
+	return it.pages.Next()
+}
 
-// TODO : Add RetrieveBusinessClassification Method
+// Customer returns the current customer.
+func (it *Iterator) Customer() *Customer {
+	return &it.items[it.pages.Index()]
+}
 
-// AddDocument uploads a document to a customer for verification
-func (cu *Customer) AddDocument(file *os.File, documentType string) error {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	if err != nil {
-		return errors.Wrap(err, "error parsing file")
-	}
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", file.Name())
-	if err != nil {
-		return errors.Wrap(err, "error uploading file")
-	}
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return errors.Wrap(err, "error uploading file")
-	}
-	err = writer.WriteField("documentType", documentType)
+// Total returns the number of customers in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
+}
+
+func listPage(ctx context.Context, c client.DwollaClient, href string) ([]Customer, client.Page, error) {
+	body := &listCustomersResponse{}
+	_, err := client.Do(ctx, c, "GET", href, nil, body)
 	if err != nil {
-		return errors.Wrap(err, "error uploading file")
+		return nil, client.Page{}, err
 	}
-	writer.Close()
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers/+"+cu.ID+"/documents", body)
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
+	customers := body.Embedded["customers"]
+	for i := range customers {
+		customers[i].Client = c
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "multipart/form-data; boundary=----WebKitFormBoundary7MA4YWxkTrZu0gW")
-	req.Header.Add("Cache-Control", "no-cache")
-	res, err := hc.Do(req)
+	return customers, body.Page, nil
+}
+
+// GetCustomer retrieves a customer belonging to the authorized Dwolla Master Account by it's ID
+func GetCustomer(ctx context.Context, c client.DwollaClient, customerID string) (*Customer, error) {
+	body := &Customer{}
+	_, err := client.Do(ctx, c, "GET", "/customers/"+customerID, nil, body)
 	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return nil
-	case 403:
-		return errors.New("not authorized to uplaod document to customer")
-	case 404:
-		return errors.New("account not found")
-	default:
-		return errors.New(res.Status)
+		return nil, err
 	}
+	body.Client = c
+	return body, nil
+}
 
+// Update sends every field of the customer to dwolla.
+//
+// Deprecated: Update sends fields dwolla does not accept for most updates.
+// Use UpdateInfo, Suspend, Deactivate, Reactivate, UpgradeToVerified
+// or RetryVerification instead, which only send the fields of the operation.
+func (cu *Customer) Update(ctx context.Context) error {
+	_, err := client.Do(ctx, cu.Client, "POST", "/customers/"+cu.ID, cu, nil)
+	return err
 }
 
-// ListDocuments retrieves documents submitted to be validated for this customer
-func (cu *Customer) ListDocuments() ([]Document, error) {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/customers/"+cu.ID+"/documents", nil)
+// ListDocuments retrieves a page of the documents submitted to be validated for this customer.
+// It also returns the number of documents in the whole list.
+func (cu *Customer) ListDocuments(ctx context.Context, opts *client.ListOptions) ([]Document, int, error) {
+	docs, page, err := listDocumentsPage(ctx, cu.Client, cu.documentsPath(opts))
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &listDocumentsResponse{}
-		err = d.Decode(body)
-		return body.Embedded["documents"], nil
-	case 403:
-		return nil, errors.New("not authorized to list customers")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, 0, err
 	}
+	return docs, page.Total, nil
 }
 
-// TODO : Add CreateDocumentForBenificialOwner method.
+// IterDocuments returns an iterator over all the documents submitted for this customer.
+// opts selects the size of the pages and where the iteration starts.
+func (cu *Customer) IterDocuments(ctx context.Context, opts *client.ListOptions) *DocumentIterator {
+	c := cu.Client
+	it := &DocumentIterator{}
+	it.pages = client.NewPageIterator(ctx, cu.documentsPath(opts), func(ctx context.Context, href string) (client.Page, int, error) {
+		docs, page, err := listDocumentsPage(ctx, c, href)
+		it.items = docs
+		return page, len(docs), err
+	})
+	return it
+}
 
-// TODO : Add ListDocumentsForBenificialOwner method.
+// Next advances to the next document. It returns false when
+// there are no more documents or a request failed.
+func (it *DocumentIterator) Next() bool {
+	return it.pages.Next()
+}
 
-// GetDocument retrieves a docuemnt by ID
-func GetDocument(c client.DwollaClient, docuemntID string) (*Document, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+// Document returns the current document.
+func (it *DocumentIterator) Document() *Document {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of documents in the whole list.
+func (it *DocumentIterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *DocumentIterator) Err() error {
+	return it.pages.Err()
+}
+
+func (cu *Customer) documentsPath(opts *client.ListOptions) string {
+	return client.WithQuery("/customers/"+cu.ID+"/documents", opts.Values())
+}
+
+func listDocumentsPage(ctx context.Context, c client.DwollaClient, href string) ([]Document, client.Page, error) {
+	body := &listDocumentsResponse{}
+	_, err := client.Do(ctx, c, "GET", href, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
+		return nil, client.Page{}, err
 	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/documents/"+docuemntID, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
+	docs := body.Embedded["documents"]
+	for i := range docs {
+		docs[i].Client = c
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+	return docs, body.Page, nil
+}
+
+// GetDocument retrieves a docuemnt by ID
+func GetDocument(ctx context.Context, c client.DwollaClient, docuemntID string) (*Document, error) {
+	body := &Document{}
+	_, err := client.Do(ctx, c, "GET", "/documents/"+docuemntID, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Document{}
-		err = d.Decode(body)
-		return body, nil
-	case 403:
-		return nil, errors.New("not authorized to retrieve the customer")
-	case 404:
-		return nil, errors.New("account not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	body.Client = c
+	return body, nil
 }
 
 // CreateFundingSource creates a funding source for a customer
-func (cu *Customer) CreateFundingSource(f *funding.Resource) error {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(f)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling customer into req body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers/"+cu.ID+"/funding-sources", bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return nil
-	case 403:
-		return errors.New("not authorized to create funding source")
-	case 400:
-		return errors.New("duplicate funding source or validation error. Authorization already associated to a funding source")
-	default:
-		return errors.New(res.Status)
-	}
+// and returns the ID and href of the new funding source.
+func (cu *Customer) CreateFundingSource(ctx context.Context, f *funding.Resource) (*client.Created, error) {
+	return client.Create(ctx, cu.Client, "/customers/"+cu.ID+"/funding-sources", f)
 }
 
-// CreateFundingSourceToken creates a new funding source from a token via dwolla.js
-func (cu *Customer) CreateFundingSourceToken() (string, error) {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+// CreateAndGetFundingSource creates a funding source like CreateFundingSource,
+// then retrieves the created funding source.
+func (cu *Customer) CreateAndGetFundingSource(ctx context.Context, f *funding.Resource) (*funding.Resource, error) {
+	created, err := cu.CreateFundingSource(ctx, f)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get auth token")
+		return nil, err
 	}
+	return funding.GetFundingSource(ctx, cu.Client, created.ID)
+}
 
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers/"+cu.ID+"/funding-sources-token", nil)
-	if err != nil {
-		return "", errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+// CreateFundingSourceToken creates a new funding source from a token via dwolla.js
+func (cu *Customer) CreateFundingSourceToken(ctx context.Context) (string, error) {
+	body := &createFudingSourceToken{}
+	_, err := client.Do(ctx, cu.Client, "POST", "/customers/"+cu.ID+"/funding-sources-token", nil, body)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &createFudingSourceToken{}
-		err = d.Decode(body)
-		return body.Token, nil
-	case 404:
-		return "", errors.New("customer not found")
-	default:
-		return "", errors.New(res.Status)
+		return "", err
 	}
+	return body.Token, nil
 }
 
 // CreateIAVFundingSourceToken creates a token to add and verify
-func (cu *Customer) CreateIAVFundingSourceToken() (string, error) {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return "", errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/customers/"+cu.ID+"/iav-token", nil)
+func (cu *Customer) CreateIAVFundingSourceToken(ctx context.Context) (string, error) {
+	body := &createFudingSourceToken{}
+	_, err := client.Do(ctx, cu.Client, "POST", "/customers/"+cu.ID+"/iav-token", nil, body)
 	if err != nil {
-		return "", errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Conetent-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &createFudingSourceToken{}
-		err = d.Decode(body)
-		return body.Token, nil
-	case 404:
-		return "", errors.New("customer not found")
-	default:
-		return "", errors.New(res.Status)
+		return "", err
 	}
+	return body.Token, nil
 }
 
 // ListFundingSources retrieves funding sources that belong to the customer.
-func (cu *Customer) ListFundingSources() ([]funding.Resource, error) {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+func (cu *Customer) ListFundingSources(ctx context.Context) ([]funding.Resource, error) {
+	body := &funding.ListResourcesResponse{}
+	_, err := client.Do(ctx, cu.Client, "GET", "/customers/"+cu.ID+"/funding-sources", nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
+		return nil, err
 	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/customers/"+cu.ID+"/funding-sources", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Conetent-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &funding.ListResourcesResponse{}
-		err = d.Decode(body)
-		var sources []funding.Resource
-		for _, s := range body.Embedded["funding-sources"] {
-			source := funding.Resource{
-				Client:          cu.Client,
-				ID:              s.ID,
-				Status:          s.Status,
-				AccountNumber:   s.AccountNumber,
-				RoutingNumber:   s.RoutingNumber,
-				BankAccountType: s.BankAccountType,
-				Name:            s.Name,
-				BankName:        s.BankName,
-				Type:            s.Type,
-				CreatedAt:       s.CreatedAt,
-				Removed:         s.Removed,
-				PlaidToken:      s.PlaidToken,
-				Channels:        s.Channels,
-				Links:           s.Links,
-			}
-			sources = append(sources, source)
-		}
-		return sources, nil
-	case 403:
-		return nil, errors.New("not authorized to list funding sources")
-	case 404:
-		return nil, errors.New("customer not found")
-	default:
-		return nil, errors.New(res.Status)
+	sources := body.Embedded["funding-sources"]
+	for i := range sources {
+		sources[i].Client = cu.Client
 	}
+	return sources, nil
 }
 
-// ListTransfers retrieves the customer's list of transfers.
-func (cu *Customer) ListTransfers() ([]transfer.Transfer, error) {
-	var c = cu.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+// ListTransfers retrieves a page of the customer's transfers.
+// It also returns the number of transfers in the whole list.
+func (cu *Customer) ListTransfers(ctx context.Context, opts *client.ListOptions) ([]transfer.Transfer, int, error) {
+	transfers, page, err := transfer.ListPage(ctx, cu.Client, cu.transfersPath(opts))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", cu.Links["self"].Href+"/transfers", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &transfer.ListTransferResponse{}
-		err = d.Decode(body)
-		return body.Embedded["transfers"], nil
-	case 403:
-		return nil, errors.New("not authorized to list transfers")
-	case 404:
-		return nil, errors.New("customer not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, 0, err
 	}
+	return transfers, page.Total, nil
+}
+
+// IterTransfers returns an iterator over all of the customer's transfers.
+// opts selects the size of the pages and where the iteration starts.
+func (cu *Customer) IterTransfers(ctx context.Context, opts *client.ListOptions) *transfer.Iterator {
+	return transfer.NewIterator(ctx, cu.Client, cu.transfersPath(opts))
+}
+
+func (cu *Customer) transfersPath(opts *client.ListOptions) string {
+	return client.WithQuery("/customers/"+cu.ID+"/transfers", opts.Values())
 }
@@ -1,13 +1,19 @@
 package customer
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
 )
 
@@ -63,6 +69,22 @@ var mockCustomers = `
 }
 `
 
+var mockDuplicateCustomer = `
+{
+  "code": "ValidationError",
+  "message": "Validation error(s) present. See embedded errors list for more details.",
+  "_embedded": {
+    "errors": [
+      {
+        "code": "Duplicate",
+        "message": "A customer with the specified email already exists.",
+        "path": "/email"
+      }
+    ]
+  }
+}
+`
+
 var mockDocument = `
 {
   "_links": {
@@ -237,7 +259,7 @@ type mockClient struct {
 func (m *mockClient) RootURL() string {
 	return m.rootURL
 }
-func (m *mockClient) Root() (map[string]map[string]string, error) {
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	account := make(map[string]string)
 	self := make(map[string]string)
@@ -248,10 +270,10 @@ func (m *mockClient) Root() (map[string]map[string]string, error) {
 	return mockLinks, nil
 }
 
-func (m *mockClient) AuthToken() (string, error) {
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
 	return m.authToken, nil
 }
-func (m *mockClient) Links() map[string]map[string]string {
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	self := make(map[string]string)
 	account := make(map[string]string)
@@ -259,9 +281,9 @@ func (m *mockClient) Links() map[string]map[string]string {
 	self["href"] = m.rootURL
 	mockLinks["self"] = self
 	mockLinks["account"] = account
-	return mockLinks
+	return mockLinks, nil
 }
-func (m *mockClient) SetAccessToken() error {
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
 	return nil
 }
 
@@ -269,6 +291,11 @@ func (m *mockClient) SetRootURL(url string) {
 	m.rootURL = url
 }
 
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
 func stubClient() *mockClient {
 	mock := &mockClient{
 		Env:          "Test",
@@ -292,35 +319,129 @@ func stubClient() *mockClient {
 }
 
 func TestCreate(t *testing.T) {
+	var got map[string]string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/customers/FC451A7A-AE30-4404-AB95-E3553FCD733F")
 		w.WriteHeader(201)
 		fmt.Fprint(w, mockCustomer)
 	}))
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer := &Customer{FirstName: "Jane", LastName: "Merchant", Email: "jmerchantere13@nomailer.com", Type: "receive-only", BusinessName: "Jane corp llc", IPAddress: "99.99.99.99"}
-	id, err := Create(mock, customer)
+	req := &ReceiveOnlyRequest{FirstName: "Jane", LastName: "Merchant", Email: "jmerchantere13@nomailer.com", BusinessName: "Jane corp llc", IPAddress: "99.99.99.99"}
+	created, err := Create(context.Background(), mock, req)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if created.ID != "FC451A7A-AE30-4404-AB95-E3553FCD733F" {
+		t.Errorf("unexpected customer id %q", created.ID)
+	}
+	if got["type"] != TypeReceiveOnly || got["firstName"] != "Jane" {
+		t.Errorf("unexpected request body %v", got)
+	}
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprint(w, mockDuplicateCustomer)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	req := &UnverifiedCustomerRequest{FirstName: "Jane", LastName: "Doe", Email: "janedoe@nomail.com"}
+	_, err := Create(context.Background(), mock, req)
+	if !errors.Is(err, client.ErrDuplicateResource) {
+		t.Errorf("expected a duplicate resource error, got %v", err)
 	}
-	t.Log(id)
 }
 
 func TestList(t *testing.T) {
+	var query url.Values
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
 		fmt.Fprint(w, mockCustomers)
 	}))
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customers, err := List(mock)
+	opts := &ListOptions{Email: "janedoe@nomail.com", Status: []Status{StatusVerified, StatusDocument}, Limit: 25}
+	customers, total, err := List(context.Background(), mock, opts)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if query.Get("email") != "janedoe@nomail.com" || len(query["status"]) != 2 || query.Get("limit") != "25" {
+		t.Errorf("unexpected query %v", query)
+	}
+	if total != 1 || customers[0].Client == nil {
+		t.Errorf("expected 1 customer with a client, got a total of %d", total)
 	}
 	t.Log(customers[0].ID)
 }
 
+func TestListOptionsValues(t *testing.T) {
+	tests := []struct {
+		opts *ListOptions
+		want string
+	}{
+		{nil, ""},
+		{&ListOptions{}, ""},
+		{&ListOptions{Search: "Jane Corp", Offset: 50}, "offset=50&search=Jane+Corp"},
+		{&ListOptions{Status: []Status{StatusRetry, StatusKBA}}, "status=retry&status=kba"},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.Values().Encode(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestIter(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprintf(w, `{"_links": {"next": {"href": "http://%s/customers?limit=1&offset=1"}}, "_embedded": {"customers": [{"id": "first"}]}, "total": 2}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"_links": {}, "_embedded": {"customers": [{"id": "second"}]}, "total": 2}`)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	it := Iter(context.Background(), mock, &ListOptions{Limit: 1})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Customer().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "first" || ids[1] != "second" || it.Total() != 2 {
+		t.Errorf("expected both pages to be iterated, got %v", ids)
+	}
+	if len(queries) != 2 || queries[0] != "limit=1" {
+		t.Errorf("unexpected page requests %v", queries)
+	}
+}
+
+func TestIterError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	it := Iter(context.Background(), mock, nil)
+	if it.Next() {
+		t.Error("expected no customers")
+	}
+	if !errors.Is(it.Err(), client.ErrForbidden) {
+		t.Errorf("expected a forbidden error, got %v", it.Err())
+	}
+}
+
 func TestGetCustomer(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, mockCustomer)
@@ -328,13 +449,30 @@ func TestGetCustomer(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
 	t.Log(customer.LastName)
 }
 
+func TestGetCustomerDeadline(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := GetCustomer(ctx, mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	if err == nil {
+		t.Error("expected an error when the deadline is exceeded")
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, mockCustomer)
@@ -342,7 +480,7 @@ func TestUpdate(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -350,37 +488,48 @@ func TestUpdate(t *testing.T) {
 	customer.Address = "12 baker street , london"
 	customer.LastName = "Tester"
 	customer.Status = "verified"
-	err = customer.Update()
+	err = customer.Update(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
 }
 
 func TestAddDocument(t *testing.T) {
+	var documentType, filename, contentType string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, mockCustomer)
+		err := r.ParseMultipartForm(1 << 20)
+		if err != nil {
+			t.Error(err)
+		}
+		documentType = r.FormValue("documentType")
+		if files := r.MultipartForm.File["file"]; len(files) == 1 {
+			filename = files[0].Filename
+			contentType = files[0].Header.Get("Content-Type")
+		}
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/documents/56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
+		w.WriteHeader(201)
 	}))
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
-	if err != nil {
-		t.Error(err)
-	}
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(201)
-		fmt.Fprint(w, mockDocument)
-	}))
-	defer ts.Close()
-	customer.Client.SetRootURL(ts.URL)
+	customer := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
 	file, err := ioutil.TempFile(".", "*.png")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	defer os.Remove(file.Name())
-	err = customer.AddDocument(file, "passport")
+	defer file.Close()
+	file.Write(mockPNG)
+	file.Seek(0, 0)
+	created, err := customer.AddDocument(context.Background(), "passport.png", file, DocumentPassport)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if created.ID != "56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc" {
+		t.Errorf("unexpected document id %q", created.ID)
+	}
+	if documentType != DocumentPassport || filename != "passport.png" || contentType != "image/png" {
+		t.Errorf("unexpected upload of %q as %q with type %q", filename, contentType, documentType)
 	}
 }
 
@@ -391,7 +540,7 @@ func TestListDocuments(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -400,7 +549,7 @@ func TestListDocuments(t *testing.T) {
 	}))
 	defer ts.Close()
 	customer.Client.SetRootURL(ts.URL)
-	_, err = customer.ListDocuments()
+	_, _, err = customer.ListDocuments(context.Background(), nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -413,7 +562,7 @@ func TestGetDocument(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	doc, err := GetDocument(mock, "56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
+	doc, err := GetDocument(context.Background(), mock, "56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
 	if err != nil {
 		t.Error(err)
 	}
@@ -427,7 +576,7 @@ func TestCreateFundingSource(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -438,12 +587,13 @@ func TestCreateFundingSource(t *testing.T) {
 		Name:            "Jane Doe's checking",
 	}
 	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/funding-sources/49dbaa24-1580-4b1c-8b58-24e26656fa31")
 		w.WriteHeader(201)
 		fmt.Fprint(w, mockFundingSource)
 	}))
 	defer ts.Close()
 	customer.Client.SetRootURL(ts.URL)
-	err = customer.CreateFundingSource(fr)
+	_, err = customer.CreateFundingSource(context.Background(), fr)
 	if err != nil {
 		t.Error(err)
 	}
@@ -456,7 +606,7 @@ func TestCreateFundingSourceToken(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -465,7 +615,7 @@ func TestCreateFundingSourceToken(t *testing.T) {
 	}))
 	defer ts.Close()
 	customer.Client.SetRootURL(ts.URL)
-	token, err := customer.CreateFundingSourceToken()
+	token, err := customer.CreateFundingSourceToken(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -478,7 +628,7 @@ func TestCreateIAVFundingSourceToken(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -487,7 +637,7 @@ func TestCreateIAVFundingSourceToken(t *testing.T) {
 	}))
 	defer ts.Close()
 	customer.Client.SetRootURL(ts.URL)
-	token, err := customer.CreateIAVFundingSourceToken()
+	token, err := customer.CreateIAVFundingSourceToken(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -500,7 +650,7 @@ func TestListFundingSources(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	customer, err := GetCustomer(mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
+	customer, err := GetCustomer(context.Background(), mock, "FC451A7A-AE30-4404-AB95-E3553FCD733F")
 	if err != nil {
 		t.Error(err)
 	}
@@ -509,7 +659,7 @@ func TestListFundingSources(t *testing.T) {
 	}))
 	defer ts.Close()
 	customer.Client.SetRootURL(ts.URL)
-	sources, err := customer.ListFundingSources()
+	sources, err := customer.ListFundingSources(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,170 @@
+package customer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/pkg/errors"
+)
+
+// MaxDocumentSize is the size in bytes of the largest document dwolla accepts.
+const MaxDocumentSize = 10 << 20
+
+// Types of documents.
+const (
+	DocumentPassport = "passport"
+	DocumentLicense  = "license"
+	DocumentIDCard   = "idCard"
+	DocumentOther    = "other"
+)
+
+// documentContentTypes are the content types of the documents dwolla accepts.
+var documentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// AddDocument uploads the document read from r to verify the identity of the
+// customer and returns the ID and href of the new document. filename is the
+// name of the file sent to dwolla and documentType is one of the Document
+// constants, such as DocumentPassport.
+//
+// The document must be a JPG, PNG or PDF of at most MaxDocumentSize bytes,
+// which is checked before and while it is sent. An error wrapping
+// ErrDocumentContentType or ErrDocumentTooLarge is returned otherwise.
+// The document is streamed to dwolla without being held in memory.
+func (cu *Customer) AddDocument(ctx context.Context, filename string, r io.Reader, documentType string) (*client.Created, error) {
+	return addDocument(ctx, cu.Client, "/customers/"+cu.ID+"/documents", filename, r, documentType)
+}
+
+// AddAndGetDocument uploads a document like AddDocument, then retrieves the created document.
+func (cu *Customer) AddAndGetDocument(ctx context.Context, filename string, r io.Reader, documentType string) (*Document, error) {
+	created, err := cu.AddDocument(ctx, filename, r, documentType)
+	if err != nil {
+		return nil, err
+	}
+	return GetDocument(ctx, cu.Client, created.ID)
+}
+
+// AddDocument uploads the document read from r to verify the identity of the
+// beneficial owner and returns the ID and href of the new document.
+// See (*Customer).AddDocument for the arguments and the checks made.
+func (o *BeneficialOwner) AddDocument(ctx context.Context, filename string, r io.Reader, documentType string) (*client.Created, error) {
+	return addDocument(ctx, o.Client, "/beneficial-owners/"+o.ID+"/documents", filename, r, documentType)
+}
+
+// AddAndGetDocument uploads a document like AddDocument, then retrieves the created document.
+func (o *BeneficialOwner) AddAndGetDocument(ctx context.Context, filename string, r io.Reader, documentType string) (*Document, error) {
+	created, err := o.AddDocument(ctx, filename, r, documentType)
+	if err != nil {
+		return nil, err
+	}
+	return GetDocument(ctx, o.Client, created.ID)
+}
+
+// addDocument streams the document read from r to the documents at path.
+func addDocument(ctx context.Context, c client.DwollaClient, path, filename string, r io.Reader, documentType string) (*client.Created, error) {
+	switch documentType {
+	case DocumentPassport, DocumentLicense, DocumentIDCard, DocumentOther:
+	default:
+		return nil, errors.Errorf("unsupported document type %q", documentType)
+	}
+	if size, ok := readerSize(r); ok && size > MaxDocumentSize {
+		return nil, errors.Wrapf(ErrDocumentTooLarge, "document has %d bytes", size)
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, errors.Wrap(err, "error reading document")
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if !documentContentTypes[contentType] {
+		return nil, errors.Wrapf(ErrDocumentContentType, "document has content type %s", contentType)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	written := make(chan error, 1)
+	go func() {
+		err := writeDocument(writer, filename, contentType, io.MultiReader(bytes.NewReader(head), r), documentType)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+	req, err := client.NewRequest(ctx, c, "POST", path, pr)
+	if err != nil {
+		pr.Close()
+		<-written
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Cache-Control", "no-cache")
+	res, err := client.DoRequest(c, req, nil)
+	pr.Close() // Stops the writer if the request ended before reading the whole body
+	werr := <-written
+	if errors.Cause(werr) == ErrDocumentTooLarge {
+		return nil, werr
+	}
+	// dwolla may answer before reading the whole document, the writer then
+	// fails on the closed pipe and the error of the request is the one to return.
+	if err != nil {
+		return nil, err
+	}
+	if werr != nil && errors.Cause(werr) != io.ErrClosedPipe {
+		return nil, werr
+	}
+	return client.NewCreated(res)
+}
+
+// writeDocument writes the multipart form of a document to w and closes w.
+func writeDocument(w *multipart.Writer, filename, contentType string, r io.Reader, documentType string) error {
+	err := w.WriteField("documentType", documentType)
+	if err != nil {
+		return err
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(part, io.LimitReader(r, MaxDocumentSize+1))
+	if err != nil {
+		return errors.Wrap(err, "error uploading document")
+	}
+	if n > MaxDocumentSize {
+		return errors.Wrapf(ErrDocumentTooLarge, "document has more than %d bytes", MaxDocumentSize)
+	}
+	return w.Close()
+}
+
+// readerSize returns the number of bytes left in r, when it is known without reading r.
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case *os.File:
+		fi, err := v.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return fi.Size() - offset, true
+	}
+	return 0, false
+}
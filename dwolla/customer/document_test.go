@@ -0,0 +1,111 @@
+package customer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
+
+// mockPNG starts like a PNG image.
+var mockPNG = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+// unsizedReader hides the length of its reader.
+type unsizedReader struct {
+	r io.Reader
+}
+
+func (u unsizedReader) Read(p []byte) (int, error) {
+	return u.r.Read(p)
+}
+
+func TestAddDocumentRejected(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		io.Copy(ioutil.Discard, r.Body)
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/documents/56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
+		w.WriteHeader(201)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	customer := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
+	large := append([]byte("%PDF-1.4\n"), make([]byte, MaxDocumentSize)...)
+	tests := []struct {
+		name string
+		r    io.Reader
+		want error
+	}{
+		{"text", strings.NewReader("not an image"), ErrDocumentContentType},
+		{"sized too large", bytes.NewReader(large), ErrDocumentTooLarge},
+		{"streamed too large", unsizedReader{bytes.NewReader(large)}, ErrDocumentTooLarge},
+	}
+	for _, tt := range tests {
+		_, err := customer.AddDocument(context.Background(), "document.pdf", tt.r, DocumentOther)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+	_, err := customer.AddDocument(context.Background(), "passport.png", bytes.NewReader(mockPNG), "selfie")
+	if err == nil {
+		t.Error("expected an error for an unsupported document type")
+	}
+	if atomic.LoadInt32(&requests) > 1 {
+		t.Errorf("expected only the streamed document to reach the server, got %d requests", requests)
+	}
+}
+
+func TestBeneficialOwnerAddAndGetDocument(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			path = r.URL.Path
+			w.Header().Set("Location", "https://api-sandbox.dwolla.com/documents/56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
+			w.WriteHeader(201)
+			return
+		}
+		fmt.Fprint(w, mockDocument)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	owner := &BeneficialOwner{Client: mock, ID: "07d59716-ef22-4fe6-98e8-f3190233dfb8"}
+	doc, err := owner.AddAndGetDocument(context.Background(), "passport.png", bytes.NewReader(mockPNG), DocumentPassport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/beneficial-owners/07d59716-ef22-4fe6-98e8-f3190233dfb8/documents" {
+		t.Errorf("unexpected upload path %s", path)
+	}
+	if doc.Type != DocumentPassport || doc.Client == nil {
+		t.Errorf("unexpected document %+v", doc)
+	}
+}
+
+func TestAddDocumentAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.dwolla.v1.hal+json")
+		w.WriteHeader(400)
+		fmt.Fprint(w, `{"code": "ValidationError", "message": "Validation error(s) present. See embedded errors list for more details."}`)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	customer := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"}
+	doc := append([]byte("%PDF-1.4\n"), make([]byte, 8<<20)...)
+	_, err := customer.AddDocument(context.Background(), "document.pdf", bytes.NewReader(doc), DocumentOther)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("expected the error of dwolla, got %v", err)
+	}
+}
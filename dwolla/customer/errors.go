@@ -0,0 +1,13 @@
+package customer
+
+import "errors"
+
+// ErrInvalidTransition is returned, wrapped, for an update that is not allowed
+// for the current status of a customer. No request is made in that case.
+var ErrInvalidTransition = errors.New("dwolla: update not allowed for the status of the customer")
+
+// Errors returned, wrapped, for documents that dwolla would reject.
+var (
+	ErrDocumentTooLarge    = errors.New("dwolla: document is larger than 10MB")
+	ErrDocumentContentType = errors.New("dwolla: document is not a JPG, PNG or PDF")
+)
@@ -0,0 +1,94 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
+
+// Verification statuses of a KBA session after answering its questions.
+const (
+	KBAVerified = "verified"
+	KBAFailed   = "failed"
+)
+
+// KBA is a knowledge based authentication session of a personal verified customer:
+// questions about the history of the customer, each with a list of answer choices.
+type KBA struct {
+	Client    client.DwollaClient    `json:"-"`
+	Links     map[string]client.Link `json:"_links"`
+	ID        string                 `json:"id"`
+	Questions []KBAQuestion          `json:"questions"`
+}
+
+// KBAQuestion is a question of a KBA session.
+type KBAQuestion struct {
+	ID      string      `json:"id"`
+	Text    string      `json:"text"`
+	Answers []KBAChoice `json:"answers"`
+}
+
+// KBAChoice is an answer choice of a KBA question.
+type KBAChoice struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// KBAAnswer is the choice selected for a KBA question.
+type KBAAnswer struct {
+	QuestionID string `json:"questionId"`
+	AnswerID   string `json:"answerId"`
+}
+
+// KBAResult is the result of answering the questions of a KBA session.
+type KBAResult struct {
+	Links              map[string]client.Link `json:"_links"`
+	VerificationStatus string                 `json:"verificationStatus"`
+}
+
+type answerKBARequest struct {
+	Answers []KBAAnswer `json:"answers"`
+}
+
+// InitiateKBA starts a KBA session for the customer
+// and returns the ID and href of the new session.
+func (cu *Customer) InitiateKBA(ctx context.Context) (*client.Created, error) {
+	return client.Create(ctx, cu.Client, "/customers/"+cu.ID+"/kba", nil)
+}
+
+// InitiateAndGetKBA starts a KBA session like InitiateKBA,
+// then retrieves its questions.
+func (cu *Customer) InitiateAndGetKBA(ctx context.Context) (*KBA, error) {
+	created, err := cu.InitiateKBA(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return GetKBA(ctx, cu.Client, created.ID)
+}
+
+// GetKBA retrieves a KBA session by ID, with its questions.
+func GetKBA(ctx context.Context, c client.DwollaClient, kbaID string) (*KBA, error) {
+	body := &KBA{}
+	_, err := client.Do(ctx, c, "GET", "/kba/"+kbaID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	body.Client = c
+	return body, nil
+}
+
+// Answer submits the answers to the questions of the session.
+// The session can be answered once; the result reports whether the customer passed.
+func (k *KBA) Answer(ctx context.Context, answers []KBAAnswer) (*KBAResult, error) {
+	body := &KBAResult{}
+	_, err := client.Do(ctx, k.Client, "POST", "/kba/"+k.ID, &answerKBARequest{Answers: answers}, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Passed reports whether the customer answered the KBA questions correctly.
+func (r *KBAResult) Passed() bool {
+	return r.VerificationStatus == KBAVerified
+}
@@ -0,0 +1,92 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var mockKBA = `
+{
+  "_links": {
+    "answer": {
+      "href": "https://api-sandbox.dwolla.com/kba/33aa88b1-97df-424a-9043-d5f85809858b"
+    }
+  },
+  "id": "33aa88b1-97df-424a-9043-d5f85809858b",
+  "questions": [
+    {
+      "id": "2355953375",
+      "text": "In what county do you currently live?",
+      "answers": [
+        {
+          "id": "2687969295",
+          "text": "Cook"
+        },
+        {
+          "id": "2687969305",
+          "text": "None of the above"
+        }
+      ]
+    },
+    {
+      "id": "2355953385",
+      "text": "Which team nickname is associated with a college you attended?",
+      "answers": [
+        {
+          "id": "2687969345",
+          "text": "Bears"
+        },
+        {
+          "id": "2687969355",
+          "text": "Wildcats"
+        }
+      ]
+    }
+  ]
+}
+`
+
+func TestKBA(t *testing.T) {
+	var answered answerKBARequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/customers/FC451A7A-AE30-4404-AB95-E3553FCD733F/kba":
+			w.Header().Set("Location", "https://api-sandbox.dwolla.com/kba/33aa88b1-97df-424a-9043-d5f85809858b")
+			w.WriteHeader(201)
+		case r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&answered)
+			fmt.Fprint(w, `{"_links": {}, "verificationStatus": "verified"}`)
+		default:
+			fmt.Fprint(w, mockKBA)
+		}
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusKBA}
+	kba, err := cu.InitiateAndGetKBA(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kba.Questions) != 2 || len(kba.Questions[0].Answers) != 2 || kba.Questions[1].Answers[1].Text != "Wildcats" {
+		t.Fatalf("unexpected questions %+v", kba.Questions)
+	}
+	var answers []KBAAnswer
+	for _, q := range kba.Questions {
+		answers = append(answers, KBAAnswer{QuestionID: q.ID, AnswerID: q.Answers[0].ID})
+	}
+	result, err := kba.Answer(context.Background(), answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed() {
+		t.Errorf("expected the customer to pass, got %+v", result)
+	}
+	if len(answered.Answers) != 2 || answered.Answers[0].QuestionID != "2355953375" || answered.Answers[0].AnswerID != "2687969295" {
+		t.Errorf("unexpected answers %+v", answered)
+	}
+}
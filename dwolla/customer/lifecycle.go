@@ -0,0 +1,109 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/pkg/errors"
+)
+
+// UpdateInfoRequest holds the information of a customer to update.
+// Empty fields are left unchanged. Verified customers can only update
+// their email, IP address, phone and address; unverified customers can
+// also update their name and business name.
+type UpdateInfoRequest struct {
+	FirstName       string `json:"firstName,omitempty"`
+	LastName        string `json:"lastName,omitempty"`
+	Email           string `json:"email,omitempty"`
+	BusinessName    string `json:"businessName,omitempty"`
+	DoingBusinessAs string `json:"doingBusinessAs,omitempty"`
+	Website         string `json:"website,omitempty"`
+	IPAddress       string `json:"ipAddress,omitempty"`
+	Address1        string `json:"address1,omitempty"`
+	Address2        string `json:"address2,omitempty"`
+	City            string `json:"city,omitempty"`
+	State           string `json:"state,omitempty"`
+	PostalCode      string `json:"postalCode,omitempty"`
+	Phone           string `json:"phone,omitempty"`
+}
+
+type statusRequest struct {
+	Status string `json:"status"`
+}
+
+// UpdateInfo updates the information of the customer.
+// The customer is refreshed from the response of dwolla.
+func (cu *Customer) UpdateInfo(ctx context.Context, info *UpdateInfoRequest) error {
+	err := cu.checkTransition(OpUpdateInfo)
+	if err != nil {
+		return err
+	}
+	if info == nil || *info == (UpdateInfoRequest{}) {
+		return errors.New("no customer information to update")
+	}
+	if cu.Status == StatusVerified && (info.FirstName != "" || info.LastName != "" || info.BusinessName != "") {
+		return errors.Wrap(ErrInvalidTransition, "cannot update the name of a verified customer")
+	}
+	return cu.post(ctx, info)
+}
+
+// Suspend the customer. A suspended customer can't send or receive funds,
+// and can only be reactivated by dwolla support.
+// The customer is refreshed from the response of dwolla.
+func (cu *Customer) Suspend(ctx context.Context) error {
+	return cu.setStatus(ctx, OpSuspend, "suspended")
+}
+
+// Deactivate the customer. A deactivated customer can't send or receive funds
+// until it is reactivated. The customer is refreshed from the response of dwolla.
+func (cu *Customer) Deactivate(ctx context.Context) error {
+	return cu.setStatus(ctx, OpDeactivate, "deactivated")
+}
+
+// Reactivate a deactivated customer.
+// The customer is refreshed from the response of dwolla.
+func (cu *Customer) Reactivate(ctx context.Context) error {
+	return cu.setStatus(ctx, OpReactivate, "reactivated")
+}
+
+// UpgradeToVerified upgrades an unverified customer to a verified customer.
+// req is a *PersonalVerifiedRequest or a *BusinessVerifiedRequest with the
+// information needed to verify the customer, it is validated first.
+// The customer is refreshed from the response of dwolla, with its new status.
+func (cu *Customer) UpgradeToVerified(ctx context.Context, req CreateRequest) error {
+	err := cu.checkTransition(OpUpgrade)
+	if err != nil {
+		return err
+	}
+	switch req.(type) {
+	case *PersonalVerifiedRequest, *BusinessVerifiedRequest:
+	default:
+		return errors.New("a customer can only be upgraded with a personal or business verified request")
+	}
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+	return cu.post(ctx, req)
+}
+
+func (cu *Customer) setStatus(ctx context.Context, op Operation, status string) error {
+	err := cu.checkTransition(op)
+	if err != nil {
+		return err
+	}
+	return cu.post(ctx, &statusRequest{Status: status})
+}
+
+// post sends body to update the customer and replaces the customer
+// with the one in the response.
+func (cu *Customer) post(ctx context.Context, body interface{}) error {
+	updated := &Customer{}
+	_, err := client.Do(ctx, cu.Client, "POST", "/customers/"+cu.ID, body, updated)
+	if err != nil {
+		return err
+	}
+	updated.Client = cu.Client
+	*cu = *updated
+	return nil
+}
@@ -0,0 +1,130 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// lifecycleServer records the bodies of the requests it receives
+// and responds with a customer of the given status.
+func lifecycleServer(t *testing.T, bodies *[]string, status Status) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(data))
+		fmt.Fprintf(w, `{"id": "FC451A7A-AE30-4404-AB95-E3553FCD733F", "firstName": "Jane", "status": %q}`, status)
+	}))
+}
+
+func TestSuspendDeactivateReactivate(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		op   func(*Customer) error
+		want string
+	}{
+		{StatusVerified, StatusSuspended, func(cu *Customer) error { return cu.Suspend(context.Background()) }, `{"status":"suspended"}`},
+		{StatusUnverified, StatusDeactivated, func(cu *Customer) error { return cu.Deactivate(context.Background()) }, `{"status":"deactivated"}`},
+		{StatusDeactivated, StatusUnverified, func(cu *Customer) error { return cu.Reactivate(context.Background()) }, `{"status":"reactivated"}`},
+	}
+	for _, tt := range tests {
+		var bodies []string
+		ts := lifecycleServer(t, &bodies, tt.to)
+		mock := stubClient()
+		mock.SetRootURL(ts.URL)
+		cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: tt.from, Email: "stale@nomail.com"}
+		err := tt.op(cu)
+		ts.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bodies) != 1 || bodies[0] != tt.want {
+			t.Errorf("expected body %s, got %v", tt.want, bodies)
+		}
+		if cu.Status != tt.to || cu.Email != "" || cu.Client == nil {
+			t.Errorf("expected the customer to be refreshed, got %+v", cu)
+		}
+	}
+}
+
+func TestLifecycleRejected(t *testing.T) {
+	var bodies []string
+	ts := lifecycleServer(t, &bodies, StatusVerified)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	suspended := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusSuspended}
+	verified := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusVerified}
+	errs := []error{
+		suspended.Deactivate(context.Background()),
+		suspended.Reactivate(context.Background()),
+		suspended.UpdateInfo(context.Background(), &UpdateInfoRequest{Email: "jane@nomail.com"}),
+		verified.UpgradeToVerified(context.Background(), retryRequest("6789")),
+		verified.UpdateInfo(context.Background(), &UpdateInfoRequest{FirstName: "John"}),
+	}
+	for i, err := range errs {
+		if !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("%d: expected an invalid transition error, got %v", i, err)
+		}
+	}
+	if len(bodies) != 0 {
+		t.Errorf("expected no requests, got %v", bodies)
+	}
+}
+
+func TestUpdateInfo(t *testing.T) {
+	var bodies []string
+	ts := lifecycleServer(t, &bodies, StatusVerified)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusVerified, SSN: "1234"}
+	err := cu.UpdateInfo(context.Background(), &UpdateInfoRequest{Email: "jane@nomail.com", Phone: "5554321234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 1 || bodies[0] != `{"email":"jane@nomail.com","phone":"5554321234"}` {
+		t.Errorf("unexpected request bodies %v", bodies)
+	}
+	for _, info := range []*UpdateInfoRequest{{}, nil} {
+		err = cu.UpdateInfo(context.Background(), info)
+		if err == nil {
+			t.Error("expected an error for an empty update")
+		}
+	}
+}
+
+func TestUpgradeToVerified(t *testing.T) {
+	var bodies []string
+	ts := lifecycleServer(t, &bodies, StatusVerified)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusUnverified}
+	err := cu.UpgradeToVerified(context.Background(), retryRequest("6789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	json.Unmarshal([]byte(bodies[0]), &got)
+	if got["type"] != TypePersonal || got["ssn"] != "6789" || cu.Status != StatusVerified {
+		t.Errorf("unexpected upgrade %v, customer %+v", got, cu)
+	}
+}
+
+func TestCustomerJSONOmitsClient(t *testing.T) {
+	mock := stubClient()
+	data, err := json.Marshal(&Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), mock.ClientSecret) || strings.Contains(string(data), "Client") {
+		t.Errorf("expected the client to be left out, got %s", data)
+	}
+}
@@ -0,0 +1,246 @@
+package customer
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Types of customers.
+const (
+	TypeUnverified  = "unverified"
+	TypeReceiveOnly = "receive-only"
+	TypePersonal    = "personal"
+	TypeBusiness    = "business"
+)
+
+// Types of businesses of business verified customers.
+const (
+	BusinessSoleProprietorship = "soleProprietorship"
+	BusinessCorporation        = "corporation"
+	BusinessLLC                = "llc"
+	BusinessPartnership        = "partnership"
+)
+
+// CreateRequest is a request to create a customer. It is one of
+// *UnverifiedCustomerRequest, *ReceiveOnlyRequest, *PersonalVerifiedRequest
+// and *BusinessVerifiedRequest.
+type CreateRequest interface {
+	// Validate returns a *ValidationError when required fields are missing.
+	Validate() error
+	customerType() string
+}
+
+// UnverifiedCustomerRequest creates an unverified customer,
+// who can send and receive funds with limits and without verification.
+type UnverifiedCustomerRequest struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Email         string `json:"email"`
+	BusinessName  string `json:"businessName,omitempty"`
+	IPAddress     string `json:"ipAddress,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// ReceiveOnlyRequest creates a receive-only user, who can only receive funds.
+type ReceiveOnlyRequest struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Email         string `json:"email"`
+	BusinessName  string `json:"businessName,omitempty"`
+	IPAddress     string `json:"ipAddress,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// PersonalVerifiedRequest creates a personal verified customer,
+// an individual whose identity is verified by dwolla.
+type PersonalVerifiedRequest struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Email         string `json:"email"`
+	IPAddress     string `json:"ipAddress,omitempty"`
+	Address1      string `json:"address1"`
+	Address2      string `json:"address2,omitempty"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	PostalCode    string `json:"postalCode"`
+	DateOfBirth   string `json:"dateOfBirth"`
+	SSN           string `json:"ssn"`
+	Phone         string `json:"phone,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// BusinessVerifiedRequest creates a business verified customer, a business
+// whose identity is verified by dwolla. FirstName, LastName and Email are
+// those of the person creating the account for the business.
+//
+// A sole proprietorship needs the DateOfBirth and SSN of its owner.
+// Other businesses need an EIN and a Controller instead.
+type BusinessVerifiedRequest struct {
+	FirstName              string      `json:"firstName"`
+	LastName               string      `json:"lastName"`
+	Email                  string      `json:"email"`
+	IPAddress              string      `json:"ipAddress,omitempty"`
+	Address1               string      `json:"address1"`
+	Address2               string      `json:"address2,omitempty"`
+	City                   string      `json:"city"`
+	State                  string      `json:"state"`
+	PostalCode             string      `json:"postalCode"`
+	BusinessName           string      `json:"businessName"`
+	DoingBusinessAs        string      `json:"doingBusinessAs,omitempty"`
+	BusinessType           string      `json:"businessType"`
+	BusinessClassification string      `json:"businessClassification"`
+	EIN                    string      `json:"ein,omitempty"`
+	Website                string      `json:"website,omitempty"`
+	Phone                  string      `json:"phone,omitempty"`
+	DateOfBirth            string      `json:"dateOfBirth,omitempty"`
+	SSN                    string      `json:"ssn,omitempty"`
+	Controller             *Controller `json:"controller,omitempty"`
+	CorrelationID          string      `json:"correlationId,omitempty"`
+}
+
+// Controller is the person with significant responsibility to control
+// a business verified customer, such as its CEO. A controller without
+// an SSN is identified by a Passport.
+type Controller struct {
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	Title       string    `json:"title"`
+	DateOfBirth string    `json:"dateOfBirth"`
+	SSN         string    `json:"ssn,omitempty"`
+	Address     *Address  `json:"address"`
+	Passport    *Passport `json:"passport,omitempty"`
+}
+
+// ValidationError is returned for a request missing required fields.
+type ValidationError struct {
+	Fields []string // JSON names of the missing fields
+}
+
+func (e *ValidationError) Error() string {
+	return "missing required fields: " + strings.Join(e.Fields, ", ")
+}
+
+// validator collects the missing fields of a request.
+type validator struct {
+	missing []string
+}
+
+func (v *validator) require(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.missing = append(v.missing, field)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.missing) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.missing}
+}
+
+// Validate implements CreateRequest.
+func (r *UnverifiedCustomerRequest) Validate() error {
+	v := &validator{}
+	v.require("firstName", r.FirstName)
+	v.require("lastName", r.LastName)
+	v.require("email", r.Email)
+	return v.err()
+}
+
+// Validate implements CreateRequest.
+func (r *ReceiveOnlyRequest) Validate() error {
+	v := &validator{}
+	v.require("firstName", r.FirstName)
+	v.require("lastName", r.LastName)
+	v.require("email", r.Email)
+	return v.err()
+}
+
+// Validate implements CreateRequest.
+func (r *PersonalVerifiedRequest) Validate() error {
+	v := &validator{}
+	v.require("firstName", r.FirstName)
+	v.require("lastName", r.LastName)
+	v.require("email", r.Email)
+	v.require("address1", r.Address1)
+	v.require("city", r.City)
+	v.require("state", r.State)
+	v.require("postalCode", r.PostalCode)
+	v.require("dateOfBirth", r.DateOfBirth)
+	v.require("ssn", r.SSN)
+	return v.err()
+}
+
+// Validate implements CreateRequest.
+func (r *BusinessVerifiedRequest) Validate() error {
+	v := &validator{}
+	v.require("firstName", r.FirstName)
+	v.require("lastName", r.LastName)
+	v.require("email", r.Email)
+	v.require("address1", r.Address1)
+	v.require("city", r.City)
+	v.require("state", r.State)
+	v.require("postalCode", r.PostalCode)
+	v.require("businessName", r.BusinessName)
+	v.require("businessType", r.BusinessType)
+	v.require("businessClassification", r.BusinessClassification)
+	if r.BusinessType == BusinessSoleProprietorship {
+		v.require("dateOfBirth", r.DateOfBirth)
+		v.require("ssn", r.SSN)
+		return v.err()
+	}
+	v.require("ein", r.EIN)
+	if r.Controller == nil {
+		v.missing = append(v.missing, "controller")
+		return v.err()
+	}
+	c := r.Controller
+	v.require("controller.firstName", c.FirstName)
+	v.require("controller.lastName", c.LastName)
+	v.require("controller.title", c.Title)
+	v.require("controller.dateOfBirth", c.DateOfBirth)
+	if c.Address == nil {
+		v.missing = append(v.missing, "controller.address")
+	} else {
+		v.require("controller.address.address1", c.Address.Address1)
+		v.require("controller.address.city", c.Address.City)
+		v.require("controller.address.stateProvinceRegion", c.Address.StateProvinceRegion)
+		v.require("controller.address.country", c.Address.Country)
+	}
+	if c.SSN == "" && c.Passport == nil {
+		v.missing = append(v.missing, "controller.ssn")
+	}
+	return v.err()
+}
+
+func (r *UnverifiedCustomerRequest) customerType() string { return "" }
+func (r *ReceiveOnlyRequest) customerType() string        { return TypeReceiveOnly }
+func (r *PersonalVerifiedRequest) customerType() string   { return TypePersonal }
+func (r *BusinessVerifiedRequest) customerType() string   { return TypeBusiness }
+
+// MarshalJSON adds the type of the customer to the request body.
+func (r *ReceiveOnlyRequest) MarshalJSON() ([]byte, error) {
+	type request ReceiveOnlyRequest
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*request
+	}{r.customerType(), (*request)(r)})
+}
+
+// MarshalJSON adds the type of the customer to the request body.
+func (r *PersonalVerifiedRequest) MarshalJSON() ([]byte, error) {
+	type request PersonalVerifiedRequest
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*request
+	}{r.customerType(), (*request)(r)})
+}
+
+// MarshalJSON adds the type of the customer to the request body.
+func (r *BusinessVerifiedRequest) MarshalJSON() ([]byte, error) {
+	type request BusinessVerifiedRequest
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*request
+	}{r.customerType(), (*request)(r)})
+}
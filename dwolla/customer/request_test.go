@@ -0,0 +1,109 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func validBusinessRequest() *BusinessVerifiedRequest {
+	return &BusinessVerifiedRequest{
+		FirstName:              "Account",
+		LastName:               "Admin",
+		Email:                  "accountadmin@nomail.net",
+		Address1:               "99-99 33rd St",
+		City:                   "Some City",
+		State:                  "NY",
+		PostalCode:             "11101",
+		BusinessName:           "Jane Corp llc",
+		BusinessType:           BusinessLLC,
+		BusinessClassification: "9ed3f670-7d6f-11e6-a2f3-7a5c5f53c7b2",
+		EIN:                    "00-0000000",
+		Controller: &Controller{
+			FirstName:   "John",
+			LastName:    "Controller",
+			Title:       "CEO",
+			DateOfBirth: "1980-01-31",
+			Address: &Address{
+				Address1:            "1749 18th st",
+				City:                "Des Moines",
+				StateProvinceRegion: "IA",
+				PostalCode:          "50266",
+				Country:             "US",
+			},
+			Passport: &Passport{Number: "JHF1234", Country: "US"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	soleProprietor := validBusinessRequest()
+	soleProprietor.BusinessType = BusinessSoleProprietorship
+	soleProprietor.EIN = ""
+	soleProprietor.Controller = nil
+	noControllerID := validBusinessRequest()
+	noControllerID.Controller.Passport = nil
+	tests := []struct {
+		name    string
+		req     CreateRequest
+		missing []string
+	}{
+		{"unverified", &UnverifiedCustomerRequest{FirstName: "Jane", LastName: "Doe", Email: "jane@nomail.net"}, nil},
+		{"receive-only without email", &ReceiveOnlyRequest{FirstName: "Jane", LastName: "Doe"}, []string{"email"}},
+		{"personal without address", &PersonalVerifiedRequest{FirstName: "Jane", LastName: "Doe", Email: "jane@nomail.net", DateOfBirth: "1970-01-01", SSN: "1234"},
+			[]string{"address1", "city", "state", "postalCode"}},
+		{"business", validBusinessRequest(), nil},
+		{"sole proprietorship", soleProprietor, []string{"dateOfBirth", "ssn"}},
+		{"controller without ssn or passport", noControllerID, []string{"controller.ssn"}},
+	}
+	for _, tt := range tests {
+		err := tt.req.Validate()
+		if tt.missing == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) || !reflect.DeepEqual(verr.Fields, tt.missing) {
+			t.Errorf("%s: expected missing fields %v, got %v", tt.name, tt.missing, err)
+		}
+	}
+}
+
+func TestCreateInvalid(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request for an invalid customer")
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	_, err := Create(context.Background(), mock, &PersonalVerifiedRequest{FirstName: "Jane"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestBusinessVerifiedRequestJSON(t *testing.T) {
+	data, err := json.Marshal(validBusinessRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Type       string `json:"type"`
+		EIN        string `json:"ein"`
+		Controller struct {
+			Address  map[string]string `json:"address"`
+			Passport map[string]string `json:"passport"`
+		} `json:"controller"`
+	}
+	json.Unmarshal(data, &got)
+	if got.Type != TypeBusiness || got.EIN != "00-0000000" || got.Controller.Address["stateProvinceRegion"] != "IA" || got.Controller.Passport["number"] != "JHF1234" {
+		t.Errorf("unexpected request body %s", data)
+	}
+}
@@ -0,0 +1,161 @@
+package customer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Status is the verification status of a customer.
+type Status string
+
+// Statuses of customers.
+const (
+	// StatusUnverified is the status of unverified customers and receive-only users.
+	StatusUnverified Status = "unverified"
+	// StatusRetry means the verification failed and can be retried once with full information.
+	StatusRetry Status = "retry"
+	// StatusDocument means a document is needed to verify the customer.
+	StatusDocument Status = "document"
+	// StatusKBA means the customer must answer knowledge based authentication questions.
+	StatusKBA Status = "kba"
+	// StatusVerified means the identity of the customer is verified.
+	StatusVerified Status = "verified"
+	// StatusSuspended means the customer is suspended, by dwolla or by the account.
+	StatusSuspended Status = "suspended"
+	// StatusDeactivated means the customer is deactivated by the account.
+	StatusDeactivated Status = "deactivated"
+)
+
+// Action is what is needed next for a customer to be able to transact.
+type Action string
+
+// Actions returned by NextAction.
+const (
+	ActionNone              Action = "none"
+	ActionRetryVerification Action = "retry-verification"
+	ActionUploadDocument    Action = "upload-document"
+	ActionAnswerKBA         Action = "answer-kba"
+	ActionContactSupport    Action = "contact-support"
+	ActionReactivate        Action = "reactivate"
+)
+
+// Operation is a change made to a customer by updating it.
+type Operation string
+
+// Operations checked against the status of a customer before an update.
+const (
+	OpUpdateInfo        Operation = "update information of"
+	OpRetryVerification Operation = "retry verification of"
+	OpUpgrade           Operation = "upgrade"
+	OpSuspend           Operation = "suspend"
+	OpDeactivate        Operation = "deactivate"
+	OpReactivate        Operation = "reactivate"
+)
+
+// transitions lists the operations allowed for each status, see CanUpdate.
+var transitions = map[Status][]Operation{
+	StatusUnverified:  {OpUpdateInfo, OpUpgrade, OpSuspend, OpDeactivate},
+	StatusRetry:       {OpRetryVerification, OpSuspend, OpDeactivate},
+	StatusDocument:    {OpSuspend, OpDeactivate},
+	StatusKBA:         {OpSuspend, OpDeactivate},
+	StatusVerified:    {OpUpdateInfo, OpSuspend, OpDeactivate},
+	StatusSuspended:   nil,
+	StatusDeactivated: {OpReactivate},
+}
+
+// NextAction returns what is needed next for the customer to be able to transact.
+func (cu *Customer) NextAction() Action {
+	switch cu.Status {
+	case StatusRetry:
+		return ActionRetryVerification
+	case StatusDocument:
+		return ActionUploadDocument
+	case StatusKBA:
+		return ActionAnswerKBA
+	case StatusSuspended:
+		return ActionContactSupport
+	case StatusDeactivated:
+		return ActionReactivate
+	}
+	return ActionNone
+}
+
+// CanUpdate reports whether op is allowed for the current status of the customer.
+// The operations allowed for each status are:
+//
+//	status       update info  retry  upgrade  suspend  deactivate  reactivate
+//	unverified   yes          -      yes      yes      yes         -
+//	retry        -            yes    -        yes      yes         -
+//	document     -            -      -        yes      yes         -
+//	kba          -            -      -        yes      yes         -
+//	verified     yes          -      -        yes      yes         -
+//	suspended    -            -      -        -        -           -
+//	deactivated  -            -      -        -        -           yes
+//
+// Suspended customers can only be reactivated by dwolla support.
+func (cu *Customer) CanUpdate(op Operation) bool {
+	for _, allowed := range transitions[cu.Status] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition returns an error wrapping ErrInvalidTransition
+// when op is not allowed for the current status of the customer.
+func (cu *Customer) checkTransition(op Operation) error {
+	if !cu.CanUpdate(op) {
+		return errors.Wrapf(ErrInvalidTransition, "cannot %s a customer with status %q", op, cu.Status)
+	}
+	return nil
+}
+
+// RetryVerification retries the verification of a customer with status retry.
+// fullInfo is a *PersonalVerifiedRequest or a *BusinessVerifiedRequest with
+// all the information of the customer and the full 9 digits of the SSN.
+// The customer is updated with the response of dwolla, including its new status.
+// Verification can only be retried once, a failed retry moves the customer
+// to status document.
+func (cu *Customer) RetryVerification(ctx context.Context, fullInfo CreateRequest) error {
+	err := cu.checkTransition(OpRetryVerification)
+	if err != nil {
+		return err
+	}
+	switch r := fullInfo.(type) {
+	case *PersonalVerifiedRequest:
+		err = requireFullSSN("ssn", r.SSN)
+	case *BusinessVerifiedRequest:
+		if r.BusinessType == BusinessSoleProprietorship {
+			err = requireFullSSN("ssn", r.SSN)
+		} else if r.Controller != nil && r.Controller.SSN != "" {
+			err = requireFullSSN("controller.ssn", r.Controller.SSN)
+		}
+	default:
+		return errors.New("verification can only be retried with a personal or business verified request")
+	}
+	if err != nil {
+		return err
+	}
+	err = fullInfo.Validate()
+	if err != nil {
+		return err
+	}
+	return cu.post(ctx, fullInfo)
+}
+
+// requireFullSSN returns an error unless ssn has 9 digits.
+func requireFullSSN(field, ssn string) error {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, ssn)
+	if len(digits) != 9 {
+		return errors.Errorf("%s must have 9 digits to retry verification", field)
+	}
+	return nil
+}
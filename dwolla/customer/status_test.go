@@ -0,0 +1,110 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func retryRequest(ssn string) *PersonalVerifiedRequest {
+	return &PersonalVerifiedRequest{
+		FirstName:   "Jane",
+		LastName:    "Doe",
+		Email:       "janedoe@nomail.com",
+		Address1:    "99-99 33rd St",
+		City:        "Some City",
+		State:       "NY",
+		PostalCode:  "11101",
+		DateOfBirth: "1970-01-01",
+		SSN:         ssn,
+	}
+}
+
+func TestNextAction(t *testing.T) {
+	tests := map[Status]Action{
+		StatusUnverified:  ActionNone,
+		StatusVerified:    ActionNone,
+		StatusRetry:       ActionRetryVerification,
+		StatusDocument:    ActionUploadDocument,
+		StatusKBA:         ActionAnswerKBA,
+		StatusSuspended:   ActionContactSupport,
+		StatusDeactivated: ActionReactivate,
+	}
+	for status, want := range tests {
+		cu := &Customer{Status: status}
+		if got := cu.NextAction(); got != want {
+			t.Errorf("%s: expected %s, got %s", status, want, got)
+		}
+	}
+}
+
+func TestCanUpdate(t *testing.T) {
+	tests := []struct {
+		status Status
+		op     Operation
+		want   bool
+	}{
+		{StatusRetry, OpRetryVerification, true},
+		{StatusVerified, OpRetryVerification, false},
+		{StatusUnverified, OpUpgrade, true},
+		{StatusVerified, OpUpgrade, false},
+		{StatusSuspended, OpDeactivate, false},
+		{StatusDeactivated, OpReactivate, true},
+		{StatusVerified, OpReactivate, false},
+	}
+	for _, tt := range tests {
+		cu := &Customer{Status: tt.status}
+		if got := cu.CanUpdate(tt.op); got != tt.want {
+			t.Errorf("%s %s: expected %v, got %v", tt.op, tt.status, tt.want, got)
+		}
+	}
+}
+
+func TestRetryVerification(t *testing.T) {
+	var got map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"id": "FC451A7A-AE30-4404-AB95-E3553FCD733F", "status": "verified"}`)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusRetry}
+	err := cu.RetryVerification(context.Background(), retryRequest("123-45-6789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["ssn"] != "123-45-6789" || got["type"] != TypePersonal {
+		t.Errorf("unexpected request body %v", got)
+	}
+	if cu.Status != StatusVerified || cu.Client == nil {
+		t.Errorf("expected the customer to be updated, got %+v", cu)
+	}
+}
+
+func TestRetryVerificationRejected(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request for a rejected retry")
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	cu := &Customer{Client: mock, ID: "FC451A7A-AE30-4404-AB95-E3553FCD733F", Status: StatusVerified}
+	err := cu.RetryVerification(context.Background(), retryRequest("123456789"))
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected an invalid transition error, got %v", err)
+	}
+	cu.Status = StatusRetry
+	err = cu.RetryVerification(context.Background(), retryRequest("6789"))
+	if err == nil {
+		t.Error("expected an error for a partial ssn")
+	}
+	err = cu.RetryVerification(context.Background(), &UnverifiedCustomerRequest{FirstName: "Jane", LastName: "Doe", Email: "janedoe@nomail.com"})
+	if err == nil {
+		t.Error("expected an error for an unverified customer request")
+	}
+}
@@ -5,11 +5,15 @@
 package dwolla
 
 import (
+	"context"
+
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/account"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/customer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/transfer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/webhook"
 )
 
 const (
@@ -25,8 +29,9 @@ type Client struct {
 }
 
 // CreateClient creates a new dwolla client.
-func CreateClient(env string, clientID string, clientSecret string) (*Client, error) {
-	client, err := client.CreateClient(env, clientID, clientSecret)
+// The client can be configured with the options of the client package.
+func CreateClient(env string, clientID string, clientSecret string, opts ...client.Option) (*Client, error) {
+	client, err := client.CreateClient(env, clientID, clientSecret, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -36,46 +41,100 @@ func CreateClient(env string, clientID string, clientSecret string) (*Client, er
 }
 
 // RetrieveAccount returns the dwolla master account.
-func (c *Client) RetrieveAccount() (*account.Account, error) {
-	return account.RetrieveAccount(c.Client)
+func (c *Client) RetrieveAccount(ctx context.Context) (*account.Account, error) {
+	return account.RetrieveAccount(ctx, c.Client)
+}
+
+// CreateCustomer creates a new customer and returns its ID and href.
+func (c *Client) CreateCustomer(ctx context.Context, req customer.CreateRequest) (*client.Created, error) {
+	return customer.Create(ctx, c.Client, req)
 }
 
-// CreateCustomer creates a new customer.
-func (c *Client) CreateCustomer(cu *customer.Customer) (string, error) {
-	return customer.Create(c.Client, cu)
+// ListCustomers retrieves a page of created customers matching opts
+// and the number of matching customers in the whole list.
+func (c *Client) ListCustomers(ctx context.Context, opts *customer.ListOptions) ([]customer.Customer, int, error) {
+	return customer.List(ctx, c.Client, opts)
 }
 
-// ListCustomers retrieves a list of created customers.
-func (c *Client) ListCustomers() ([]customer.Customer, error) {
-	return customer.List(c.Client)
+// IterCustomers returns an iterator over all created customers matching opts.
+func (c *Client) IterCustomers(ctx context.Context, opts *customer.ListOptions) *customer.Iterator {
+	return customer.Iter(ctx, c.Client, opts)
 }
 
 // GetCustomer retrieves a customer by ID.
-func (c *Client) GetCustomer(customerID string) (*customer.Customer, error) {
-	return customer.GetCustomer(c.Client, customerID)
+func (c *Client) GetCustomer(ctx context.Context, customerID string) (*customer.Customer, error) {
+	return customer.GetCustomer(ctx, c.Client, customerID)
 }
 
 // GetDocument retrieves a document by ID.
-func (c *Client) GetDocument(documentID string) (*customer.Document, error) {
-	return customer.GetDocument(c.Client, documentID)
+func (c *Client) GetDocument(ctx context.Context, documentID string) (*customer.Document, error) {
+	return customer.GetDocument(ctx, c.Client, documentID)
+}
+
+// ListBusinessClassifications retrieves the business classifications
+// with their industry classifications.
+func (c *Client) ListBusinessClassifications(ctx context.Context) ([]customer.BusinessClassification, int, error) {
+	return customer.ListBusinessClassifications(ctx, c.Client)
+}
+
+// GetKBA retrieves a knowledge based authentication session by ID, with its questions.
+func (c *Client) GetKBA(ctx context.Context, kbaID string) (*customer.KBA, error) {
+	return customer.GetKBA(ctx, c.Client, kbaID)
+}
+
+// GetBeneficialOwner retrieves a beneficial owner of a customer by ID.
+func (c *Client) GetBeneficialOwner(ctx context.Context, ownerID string) (*customer.BeneficialOwner, error) {
+	return customer.GetBeneficialOwner(ctx, c.Client, ownerID)
 }
 
 // GetFundingSource retrieves a funding source by ID.
-func (c *Client) GetFundingSource(sourceID string) (*funding.Resource, error) {
-	return funding.GetFundingSource(c.Client, sourceID)
+func (c *Client) GetFundingSource(ctx context.Context, sourceID string) (*funding.Resource, error) {
+	return funding.GetFundingSource(ctx, c.Client, sourceID)
 }
 
-// CreateTransfer creates a transfer between two funding sources
-func (c *Client) CreateTransfer(t *transfer.Transfer) error {
-	return transfer.CreateTransfer(c.Client, t)
+// CreateTransfer creates a transfer between two funding sources and returns its ID and href.
+func (c *Client) CreateTransfer(ctx context.Context, t *transfer.Transfer) (*client.Created, error) {
+	return transfer.CreateTransfer(ctx, c.Client, t)
 }
 
 // GetTransfer retrieves a transfer by it's ID.
-func (c *Client) GetTransfer(transferID string) (*transfer.Transfer, error) {
-	return transfer.GetTransfer(c.Client, transferID)
+func (c *Client) GetTransfer(ctx context.Context, transferID string) (*transfer.Transfer, error) {
+	return transfer.GetTransfer(ctx, c.Client, transferID)
 }
 
 // CreateOnDemandAuth creates an on-demand token.
-func (c *Client) CreateOnDemandAuth() (string, error) {
-	return transfer.CreateOnDemandAuth(c.Client)
+func (c *Client) CreateOnDemandAuth(ctx context.Context) (string, error) {
+	return transfer.CreateOnDemandAuth(ctx, c.Client)
+}
+
+// CreateWebhookSubscription subscribes url to the webhooks of the account.
+// secret is used by dwolla to sign each webhook.
+func (c *Client) CreateWebhookSubscription(ctx context.Context, url, secret string) (*client.Created, error) {
+	return webhook.CreateSubscription(ctx, c.Client, url, secret)
+}
+
+// ListWebhookSubscriptions retrieves the webhook subscriptions of the account.
+func (c *Client) ListWebhookSubscriptions(ctx context.Context) ([]webhook.Subscription, int, error) {
+	return webhook.ListSubscriptions(ctx, c.Client)
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by ID.
+func (c *Client) GetWebhookSubscription(ctx context.Context, subscriptionID string) (*webhook.Subscription, error) {
+	return webhook.GetSubscription(ctx, c.Client, subscriptionID)
+}
+
+// GetWebhook retrieves a webhook by ID, with its delivery attempts.
+func (c *Client) GetWebhook(ctx context.Context, webhookID string) (*webhook.Webhook, error) {
+	return webhook.GetWebhook(ctx, c.Client, webhookID)
+}
+
+// ListEvents retrieves a page of the events of the account, newest first,
+// and the number of events in the whole list.
+func (c *Client) ListEvents(ctx context.Context, opts *client.ListOptions) ([]events.Event, int, error) {
+	return events.List(ctx, c.Client, opts)
+}
+
+// GetEvent retrieves an event by ID.
+func (c *Client) GetEvent(ctx context.Context, eventID string) (*events.Event, error) {
+	return events.Get(ctx, c.Client, eventID)
 }
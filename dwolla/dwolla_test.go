@@ -1,6 +1,7 @@
 package dwolla
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,8 +9,11 @@ import (
 
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/customer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/transfer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/webhook"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/webhook/webhooktest"
 )
 
 var mockAccount = `{
@@ -211,7 +215,7 @@ type mockClient struct {
 func (m *mockClient) RootURL() string {
 	return m.rootURL
 }
-func (m *mockClient) Root() (map[string]map[string]string, error) {
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	account := make(map[string]string)
 	self := make(map[string]string)
@@ -222,10 +226,10 @@ func (m *mockClient) Root() (map[string]map[string]string, error) {
 	return mockLinks, nil
 }
 
-func (m *mockClient) AuthToken() (string, error) {
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
 	return m.authToken, nil
 }
-func (m *mockClient) Links() map[string]map[string]string {
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	self := make(map[string]string)
 	account := make(map[string]string)
@@ -233,9 +237,9 @@ func (m *mockClient) Links() map[string]map[string]string {
 	self["href"] = m.rootURL
 	mockLinks["self"] = self
 	mockLinks["account"] = account
-	return mockLinks
+	return mockLinks, nil
 }
-func (m *mockClient) SetAccessToken() error {
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
 	return nil
 }
 
@@ -243,6 +247,11 @@ func (m *mockClient) SetRootURL(url string) {
 	m.rootURL = url
 }
 
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
 func stubClient() *Client {
 	mock := &mockClient{
 		Env:          "Test",
@@ -274,7 +283,7 @@ func TestRetrieveAccount(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	account, err := mock.RetrieveAccount()
+	account, err := mock.RetrieveAccount(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -284,13 +293,14 @@ func TestRetrieveAccount(t *testing.T) {
 func TestCreateCustomer(t *testing.T) {
 	mock := stubClient()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/customers/FC451A7A-AE30-4404-AB95-E3553FCD733F")
 		w.WriteHeader(201)
 		fmt.Fprint(w, mockCustomer)
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	customer := &customer.Customer{FirstName: "Jane", LastName: "Merchant", Email: "jmerchantere13@nomailer.com", Type: "receive-only", BusinessName: "Jane corp llc", IPAddress: "99.99.99.99"}
-	_, err := mock.CreateCustomer(customer)
+	req := &customer.ReceiveOnlyRequest{FirstName: "Jane", LastName: "Merchant", Email: "jmerchantere13@nomailer.com", BusinessName: "Jane corp llc", IPAddress: "99.99.99.99"}
+	_, err := mock.CreateCustomer(context.Background(), req)
 	if err != nil {
 		t.Error(err)
 	}
@@ -303,7 +313,7 @@ func TestListCustomers(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	customers, err := mock.ListCustomers()
+	customers, _, err := mock.ListCustomers(context.Background(), nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -317,7 +327,7 @@ func TestGetCustomer(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	customer, err := mock.GetCustomer("ca32853c-48fa-40be-ae75-77b37504581b")
+	customer, err := mock.GetCustomer(context.Background(), "ca32853c-48fa-40be-ae75-77b37504581b")
 	if err != nil {
 		t.Error(err)
 	}
@@ -331,7 +341,7 @@ func TestGetDocument(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	doc, err := mock.GetDocument("56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
+	doc, err := mock.GetDocument(context.Background(), "56502f7a-fa59-4a2f-8579-0f8bc9d7b9cc")
 	if err != nil {
 		t.Error(err)
 	}
@@ -345,7 +355,7 @@ func TestGetFundingSource(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	doc, err := mock.GetFundingSource("49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	doc, err := mock.GetFundingSource(context.Background(), "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -355,6 +365,7 @@ func TestGetFundingSource(t *testing.T) {
 func TestCreateTransfer(t *testing.T) {
 	mock := stubClient()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8")
 		w.WriteHeader(201)
 		fmt.Fprint(w, "Created")
 	}))
@@ -375,7 +386,7 @@ func TestCreateTransfer(t *testing.T) {
 		Amount: amount,
 		Links:  links,
 	}
-	err := mock.CreateTransfer(tr)
+	_, err := mock.CreateTransfer(context.Background(), tr)
 	if err != nil {
 		t.Error(err)
 	}
@@ -388,7 +399,7 @@ func TestGetTransfer(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	transfer, err := mock.GetTransfer("15c6bcce-46f7-e811-8112-e8dd3bececa8")
+	transfer, err := mock.GetTransfer(context.Background(), "15c6bcce-46f7-e811-8112-e8dd3bececa8")
 	if err != nil {
 		t.Error(err)
 	}
@@ -402,9 +413,36 @@ func TestCreateOnDemandAuth(t *testing.T) {
 	}))
 	defer ts.Close()
 	mock.Client.SetRootURL(ts.URL)
-	link, err := mock.CreateOnDemandAuth()
+	link, err := mock.CreateOnDemandAuth(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
 	t.Log("On Demand Link = ", link)
 }
+
+func TestWebhookEndToEnd(t *testing.T) {
+	mock := stubClient()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockTransfer)
+	}))
+	defer ts.Close()
+	mock.Client.SetRootURL(ts.URL)
+	var got *transfer.Transfer
+	h := webhook.NewHandler("your webhook secret", webhook.WithClient(mock.Client))
+	h.OnTransferCompleted(func(ctx context.Context, evt *events.Event, tr *transfer.Transfer) error {
+		got = tr
+		return nil
+	})
+	sim := webhooktest.NewSimulator("your webhook secret")
+	sim.BaseURL = ts.URL
+	res, err := sim.Serve(h, sim.NewEvent(events.TopicTransferCompleted, "/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	if got == nil || got.ID != "15c6bcce-46f7-e811-8112-e8dd3bececa8" {
+		t.Errorf("unexpected transfer %+v", got)
+	}
+}
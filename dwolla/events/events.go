@@ -0,0 +1,127 @@
+// Package events provides the events of a dwolla account,
+// as delivered by webhooks and listed by the dwolla api.
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
+
+// Event is a change to a resource of the account, such as a created customer
+// or a completed transfer. Its links point at the resource and, when the
+// resource belongs to one, at the customer.
+type Event struct {
+	Links      map[string]client.Link `json:"_links"`
+	ID         string                 `json:"id"`
+	Topic      Topic                  `json:"topic"`
+	ResourceID string                 `json:"resourceId"`
+	Timestamp  time.Time              `json:"timestamp"`
+	CreatedAt  time.Time              `json:"created"`
+}
+
+// ListEventsResponse is the response that is returned by dwolla to list events.
+type ListEventsResponse struct {
+	client.Page
+	Embedded map[string][]Event `json:"_embedded"`
+}
+
+// Iterator iterates over the events of the account, newest first,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []Event
+	until string // ID of the event the iteration stops at, if any
+	done  bool
+}
+
+// ResourceHref returns the href of the resource the event is about.
+func (e *Event) ResourceHref() string {
+	return e.Links["resource"].Href
+}
+
+// CustomerHref returns the href of the customer the resource belongs to,
+// or an empty string when the resource does not belong to a customer.
+func (e *Event) CustomerHref() string {
+	return e.Links["customer"].Href
+}
+
+// List retrieves a page of the events of the account, newest first.
+// It also returns the number of events in the whole list.
+func List(ctx context.Context, c client.DwollaClient, opts *client.ListOptions) ([]Event, int, error) {
+	evts, page, err := listPage(ctx, c, client.WithQuery("/events", opts.Values()))
+	if err != nil {
+		return nil, 0, err
+	}
+	return evts, page.Total, nil
+}
+
+// Get retrieves an event by ID.
+func Get(ctx context.Context, c client.DwollaClient, eventID string) (*Event, error) {
+	body := &Event{}
+	_, err := client.Do(ctx, c, "GET", "/events/"+eventID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Iter returns an iterator over all the events of the account, newest first.
+// opts selects the size of the pages and where the iteration starts.
+func Iter(ctx context.Context, c client.DwollaClient, opts *client.ListOptions) *Iterator {
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, client.WithQuery("/events", opts.Values()), func(ctx context.Context, href string) (client.Page, int, error) {
+		evts, page, err := listPage(ctx, c, href)
+		it.items = evts
+		return page, len(evts), err
+	})
+	return it
+}
+
+// IterUntil returns an iterator over the events of the account that are newer
+// than the event with the given ID, newest first. The iteration stops before
+// that event, or at the end of the list when no event has that ID.
+// opts selects the size of the pages.
+func IterUntil(ctx context.Context, c client.DwollaClient, eventID string, opts *client.ListOptions) *Iterator {
+	it := Iter(ctx, c, opts)
+	it.until = eventID
+	return it
+}
+
+// Next advances to the next event. It returns false when there are no more
+// events, the event to stop at was reached, or a request failed.
+func (it *Iterator) Next() bool {
+	if it.done || !it.pages.Next() {
+		return false
+	}
+	if it.until != "" && it.Event().ID == it.until {
+		it.done = true
+		return false
+	}
+	return true
+}
+
+// Event returns the current event.
+func (it *Iterator) Event() *Event {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of events in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
+}
+
+func listPage(ctx context.Context, c client.DwollaClient, href string) ([]Event, client.Page, error) {
+	body := &ListEventsResponse{}
+	_, err := client.Do(ctx, c, "GET", href, nil, body)
+	if err != nil {
+		return nil, client.Page{}, err
+	}
+	return body.Embedded["events"], body.Page, nil
+}
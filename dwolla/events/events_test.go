@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var mockEvent = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/events/81f6e13c-557c-4449-9331-da5c65e61095"
+    },
+    "resource": {
+      "href": "https://api-sandbox.dwolla.com/transfers/47CFDDB4-1E74-E511-80DB-0AA34A9B2388"
+    },
+    "account": {
+      "href": "https://api-sandbox.dwolla.com/accounts/ca32853c-48fa-40be-ae75-77b37504581b"
+    }
+  },
+  "id": "81f6e13c-557c-4449-9331-da5c65e61095",
+  "created": "2015-10-16T15:58:15.000Z",
+  "topic": "transfer_created",
+  "resourceId": "47CFDDB4-1E74-E511-80DB-0AA34A9B2388"
+}
+`
+
+type mockClient struct {
+	authToken string
+	rootURL   string
+}
+
+func (m *mockClient) RootURL() string {
+	return m.rootURL
+}
+
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
+	return m.Links(ctx)
+}
+
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
+	return m.authToken, nil
+}
+
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
+	mockLinks := make(map[string]map[string]string)
+	mockLinks["self"] = map[string]string{"href": m.rootURL}
+	mockLinks["account"] = map[string]string{"href": m.rootURL + "/account"}
+	return mockLinks, nil
+}
+
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
+	return nil
+}
+
+func (m *mockClient) SetRootURL(url string) {
+	m.rootURL = url
+}
+
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
+func stubClient() *mockClient {
+	return &mockClient{
+		authToken: "abcdefghijklmn",
+		rootURL:   "http://localhost:8080",
+	}
+}
+
+// eventsServer serves a list of n events, newest first, named event-n to event-1,
+// in pages of two events.
+func eventsServer(n int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		next := ""
+		if offset+2 < n {
+			next = fmt.Sprintf(`"next": {"href": "http://%s/events?limit=2&offset=%d"}`, r.Host, offset+2)
+		}
+		items := ""
+		for i := offset; i < offset+2 && i < n; i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"id": "event-%d", "topic": "customer_created"}`, n-i)
+		}
+		fmt.Fprintf(w, `{"_links": {%s}, "_embedded": {"events": [%s]}, "total": %d}`, next, items, n)
+	}))
+}
+
+func TestList(t *testing.T) {
+	ts := eventsServer(5)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	evts, total, err := List(context.Background(), mock, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || len(evts) != 2 || evts[0].ID != "event-5" {
+		t.Errorf("unexpected events %+v with a total of %d", evts, total)
+	}
+}
+
+func TestGet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockEvent)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	evt, err := Get(context.Background(), mock, "81f6e13c-557c-4449-9331-da5c65e61095")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evt.Topic != "transfer_created" || evt.ResourceHref() == "" || evt.CreatedAt.IsZero() {
+		t.Errorf("unexpected event %+v", evt)
+	}
+}
+
+func TestIterUntil(t *testing.T) {
+	ts := eventsServer(5)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	var ids []string
+	it := IterUntil(context.Background(), mock, "event-2", nil)
+	for it.Next() {
+		ids = append(ids, it.Event().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[event-5 event-4 event-3]" {
+		t.Errorf("unexpected events %v", ids)
+	}
+
+	ids = nil
+	it = Iter(context.Background(), mock, nil)
+	for it.Next() {
+		ids = append(ids, it.Event().ID)
+	}
+	if len(ids) != 5 {
+		t.Errorf("expected every event, got %v", ids)
+	}
+}
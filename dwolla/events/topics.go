@@ -0,0 +1,81 @@
+package events
+
+// Topic is the kind of change an event describes.
+type Topic string
+
+// Topics of the events about the master account and its resources.
+const (
+	TopicAccountSuspended         Topic = "account_suspended"
+	TopicAccountActivated         Topic = "account_activated"
+	TopicFundingSourceAdded       Topic = "funding_source_added"
+	TopicFundingSourceRemoved     Topic = "funding_source_removed"
+	TopicFundingSourceVerified    Topic = "funding_source_verified"
+	TopicFundingSourceUnverified  Topic = "funding_source_unverified"
+	TopicFundingSourceNegative    Topic = "funding_source_negative"
+	TopicFundingSourceUpdated     Topic = "funding_source_updated"
+	TopicMicroDepositsAdded       Topic = "microdeposits_added"
+	TopicMicroDepositsFailed      Topic = "microdeposits_failed"
+	TopicMicroDepositsCompleted   Topic = "microdeposits_completed"
+	TopicMicroDepositsMaxAttempts Topic = "microdeposits_maxattempts"
+	TopicBankTransferCreated      Topic = "bank_transfer_created"
+	TopicBankTransferCancelled    Topic = "bank_transfer_cancelled"
+	TopicBankTransferFailed       Topic = "bank_transfer_failed"
+	TopicBankTransferCompleted    Topic = "bank_transfer_completed"
+	TopicTransferCreated          Topic = "transfer_created"
+	TopicTransferCancelled        Topic = "transfer_cancelled"
+	TopicTransferFailed           Topic = "transfer_failed"
+	TopicTransferReclaimed        Topic = "transfer_reclaimed"
+	TopicTransferCompleted        Topic = "transfer_completed"
+	TopicMassPaymentCreated       Topic = "mass_payment_created"
+	TopicMassPaymentCompleted     Topic = "mass_payment_completed"
+	TopicMassPaymentCancelled     Topic = "mass_payment_cancelled"
+	TopicStatementCreated         Topic = "statement_created"
+)
+
+// Topics of the events about customers and their resources.
+const (
+	TopicCustomerCreated                                     Topic = "customer_created"
+	TopicCustomerKBAVerificationNeeded                       Topic = "customer_kba_verification_needed"
+	TopicCustomerKBAVerificationFailed                       Topic = "customer_kba_verification_failed"
+	TopicCustomerKBAVerificationPassed                       Topic = "customer_kba_verification_passed"
+	TopicCustomerVerificationDocumentNeeded                  Topic = "customer_verification_document_needed"
+	TopicCustomerVerificationDocumentUploaded                Topic = "customer_verification_document_uploaded"
+	TopicCustomerVerificationDocumentFailed                  Topic = "customer_verification_document_failed"
+	TopicCustomerVerificationDocumentApproved                Topic = "customer_verification_document_approved"
+	TopicCustomerReverificationNeeded                        Topic = "customer_reverification_needed"
+	TopicCustomerVerified                                    Topic = "customer_verified"
+	TopicCustomerSuspended                                   Topic = "customer_suspended"
+	TopicCustomerActivated                                   Topic = "customer_activated"
+	TopicCustomerDeactivated                                 Topic = "customer_deactivated"
+	TopicCustomerBeneficialOwnerCreated                      Topic = "customer_beneficial_owner_created"
+	TopicCustomerBeneficialOwnerRemoved                      Topic = "customer_beneficial_owner_removed"
+	TopicCustomerBeneficialOwnerVerificationDocumentNeeded   Topic = "customer_beneficial_owner_verification_document_needed"
+	TopicCustomerBeneficialOwnerVerificationDocumentUploaded Topic = "customer_beneficial_owner_verification_document_uploaded"
+	TopicCustomerBeneficialOwnerVerificationDocumentFailed   Topic = "customer_beneficial_owner_verification_document_failed"
+	TopicCustomerBeneficialOwnerVerificationDocumentApproved Topic = "customer_beneficial_owner_verification_document_approved"
+	TopicCustomerBeneficialOwnerReverificationNeeded         Topic = "customer_beneficial_owner_reverification_needed"
+	TopicCustomerBeneficialOwnerVerified                     Topic = "customer_beneficial_owner_verified"
+	TopicCustomerFundingSourceAdded                          Topic = "customer_funding_source_added"
+	TopicCustomerFundingSourceRemoved                        Topic = "customer_funding_source_removed"
+	TopicCustomerFundingSourceVerified                       Topic = "customer_funding_source_verified"
+	TopicCustomerFundingSourceUnverified                     Topic = "customer_funding_source_unverified"
+	TopicCustomerFundingSourceNegative                       Topic = "customer_funding_source_negative"
+	TopicCustomerFundingSourceUpdated                        Topic = "customer_funding_source_updated"
+	TopicCustomerMicroDepositsAdded                          Topic = "customer_microdeposits_added"
+	TopicCustomerMicroDepositsFailed                         Topic = "customer_microdeposits_failed"
+	TopicCustomerMicroDepositsCompleted                      Topic = "customer_microdeposits_completed"
+	TopicCustomerMicroDepositsMaxAttempts                    Topic = "customer_microdeposits_maxattempts"
+	TopicCustomerBankTransferCreated                         Topic = "customer_bank_transfer_created"
+	TopicCustomerBankTransferCreationFailed                  Topic = "customer_bank_transfer_creation_failed"
+	TopicCustomerBankTransferCancelled                       Topic = "customer_bank_transfer_cancelled"
+	TopicCustomerBankTransferFailed                          Topic = "customer_bank_transfer_failed"
+	TopicCustomerBankTransferCompleted                       Topic = "customer_bank_transfer_completed"
+	TopicCustomerTransferCreated                             Topic = "customer_transfer_created"
+	TopicCustomerTransferCancelled                           Topic = "customer_transfer_cancelled"
+	TopicCustomerTransferFailed                              Topic = "customer_transfer_failed"
+	TopicCustomerTransferCompleted                           Topic = "customer_transfer_completed"
+	TopicCustomerMassPaymentCreated                          Topic = "customer_mass_payment_created"
+	TopicCustomerMassPaymentCompleted                        Topic = "customer_mass_payment_completed"
+	TopicCustomerMassPaymentCancelled                        Topic = "customer_mass_payment_cancelled"
+	TopicCustomerBalanceInquiryCompleted                     Topic = "customer_balance_inquiry_completed"
+)
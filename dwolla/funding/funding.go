@@ -2,9 +2,7 @@
 package funding
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
+	"context"
 
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/pkg/errors"
@@ -12,7 +10,7 @@ import (
 
 // Resource represents a bank account connected to dwolla account.
 type Resource struct {
-	Client          client.DwollaClient
+	Client          client.DwollaClient    `json:"-"`
 	ID              string                 `json:"id"`
 	Status          string                 `json:"status"`
 	AccountNumber   string                 `json:"accountNumber"`
@@ -31,8 +29,15 @@ type Resource struct {
 // ListResourcesResponse is the response that is returned by dwolla
 // to list funding resources request.
 type ListResourcesResponse struct {
-	Links    map[string]client.Link `json:"_links"`
-	Embedded map[string][]Resource  `json:"_embedded"`
+	client.Page
+	Embedded map[string][]Resource `json:"_embedded"`
+}
+
+// Iterator iterates over the funding sources of a list,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []Resource
 }
 
 // VerifyMicroDepositsRequest is the request to verify microdeposits
@@ -56,224 +61,119 @@ type BalanceResponse struct {
 
 // MicroDepositsDetails has the details for a microdeposits and their status.
 type MicroDepositsDetails struct {
-	Links     map[string]map[string]client.Link `json:"_links"`
-	CreatedAt string                            `json:"created"`
-	Status    string                            `json:"status"`
-	Failure   map[string]string                 `json:"failure"`
+	Links     map[string]client.Link `json:"_links"`
+	CreatedAt string                 `json:"created"`
+	Status    string                 `json:"status"`
+	Failure   map[string]string      `json:"failure"`
 }
 
 // GetFundingSource retrieves a funding source by id.
-func GetFundingSource(c client.DwollaClient, sourceID string) (*Resource, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/funding-sources/"+sourceID, nil)
+func GetFundingSource(ctx context.Context, c client.DwollaClient, sourceID string) (*Resource, error) {
+	body := &Resource{}
+	_, err := client.Do(ctx, c, "GET", "/funding-sources/"+sourceID, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
+		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+	body.Client = c
+	return body, nil
+}
+
+// ListPage retrieves the page of funding sources at hrefOrPath.
+// It returns the funding sources of the page and its pagination fields.
+func ListPage(ctx context.Context, c client.DwollaClient, hrefOrPath string) ([]Resource, client.Page, error) {
+	body := &ListResourcesResponse{}
+	_, err := client.Do(ctx, c, "GET", hrefOrPath, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
+		return nil, client.Page{}, err
 	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Resource{}
-		err = d.Decode(body)
-		body.Client = c
-		return body, nil
-	case 404:
-		return nil, errors.New("customer not found")
-	default:
-		return nil, errors.New(res.Status)
+	sources := body.Embedded["funding-sources"]
+	for i := range sources {
+		sources[i].Client = c
 	}
+	return sources, body.Page, nil
+}
+
+// NewIterator returns an iterator over the funding sources listed at hrefOrPath.
+func NewIterator(ctx context.Context, c client.DwollaClient, hrefOrPath string) *Iterator {
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, hrefOrPath, func(ctx context.Context, href string) (client.Page, int, error) {
+		sources, page, err := ListPage(ctx, c, href)
+		it.items = sources
+		return page, len(sources), err
+	})
+	return it
+}
+
+// Next advances to the next funding source. It returns false when
+// there are no more funding sources or a request failed.
+func (it *Iterator) Next() bool {
+	return it.pages.Next()
+}
+
+// Resource returns the current funding source.
+func (it *Iterator) Resource() *Resource {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of funding sources in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
 }
 
 // Update a funding source.
-func (f *Resource) Update() error {
-	var c = f.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(f)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling json body for request")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/funding-sources/"+f.ID, bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		return nil
-	case 404:
-		return errors.New("funding source not found")
-	case 400:
-		return errors.New("only funding sources of type bank can be updated")
-	case 403:
-		return errors.New("a removed bank account cannot be updated")
-	default:
-		return errors.New(res.Status)
-	}
+func (f *Resource) Update(ctx context.Context) error {
+	_, err := client.Do(ctx, f.Client, "POST", "/funding-sources/"+f.ID, f, nil)
+	return err
 }
 
 // IntiateMicroDeposits for bank account verification.
-func (f *Resource) IntiateMicroDeposits() error {
-	var c = f.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/funding-sources/"+f.ID+"/micro-deposits", nil)
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return nil
-	case 404:
-		return errors.New("funding source not found")
-	default:
-		return errors.New(res.Status)
-	}
+func (f *Resource) IntiateMicroDeposits(ctx context.Context) error {
+	_, err := client.Do(ctx, f.Client, "POST", "/funding-sources/"+f.ID+"/micro-deposits", nil, nil)
+	return err
 }
 
 // VerifyMicroDeposits bank verification.
-func (f *Resource) VerifyMicroDeposits(vr *VerifyMicroDepositsRequest) error {
-	var c = f.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(vr)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling verify micro deposits")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/funding-sources/"+f.ID+"/micro-deposits", bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+func (f *Resource) VerifyMicroDeposits(ctx context.Context, vr *VerifyMicroDepositsRequest) error {
+	res, err := client.Do(ctx, f.Client, "POST", "/funding-sources/"+f.ID+"/micro-deposits", vr, nil)
 	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
+		return err
 	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		return nil
-	case 202:
+	if res.StatusCode == 202 {
 		return errors.New("Micro-deposits have not have not settled to destination bank. A Customer can verify these amounts after micro-deposits have processed to their bank")
-	case 403:
-		return errors.New("too many attempts, bank already verified")
-	case 404:
-		return errors.New("micro deposits not initiated or funding source not found")
-	case 500:
-		return errors.New("verify micro-deposits returned an unknown error")
-	default:
-		return errors.New(res.Status)
 	}
+	return nil
 }
 
 // GetBalance retrieves balance for the funding source.
-func (f *Resource) GetBalance() (*BalanceResponse, error) {
-	var c = f.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/funding-sources/"+f.ID+"/balance", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+func (f *Resource) GetBalance(ctx context.Context) (*BalanceResponse, error) {
+	body := &BalanceResponse{}
+	_, err := client.Do(ctx, f.Client, "GET", "/funding-sources/"+f.ID+"/balance", nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &BalanceResponse{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error decoding JSON body")
-		}
-		return body, nil
-	case 404:
-		return nil, errors.New("funding source not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	return body, nil
 }
 
 // GetMicroDepositsDetails retrieves the status of micro-deposits
 // and checks if they are eligible for verification.
-func (f *Resource) GetMicroDepositsDetails() (*MicroDepositsDetails, error) {
-	var c = f.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/funding-sources/"+f.ID+"/micro-deposits", nil)
+func (f *Resource) GetMicroDepositsDetails(ctx context.Context) (*MicroDepositsDetails, error) {
+	body := &MicroDepositsDetails{}
+	_, err := client.Do(ctx, f.Client, "GET", "/funding-sources/"+f.ID+"/micro-deposits", nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &MicroDepositsDetails{}
-		err = d.Decode(body)
-		return body, nil
-	case 404:
-		return nil, errors.New("micro-depostis not found or have already been verified")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	return body, nil
 }
 
 // Remove a funding resource.
-func (f *Resource) Remove() error {
+func (f *Resource) Remove(ctx context.Context) error {
 	f.Removed = true
-	err := f.Update()
+	err := f.Update(ctx)
 	if err != nil {
 		return errors.Wrap(err, "error removing funding source")
 	}
@@ -1,6 +1,7 @@
 package funding
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -102,7 +103,7 @@ type mockClient struct {
 func (m *mockClient) RootURL() string {
 	return m.rootURL
 }
-func (m *mockClient) Root() (map[string]map[string]string, error) {
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	account := make(map[string]string)
 	self := make(map[string]string)
@@ -113,10 +114,10 @@ func (m *mockClient) Root() (map[string]map[string]string, error) {
 	return mockLinks, nil
 }
 
-func (m *mockClient) AuthToken() (string, error) {
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
 	return m.authToken, nil
 }
-func (m *mockClient) Links() map[string]map[string]string {
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	self := make(map[string]string)
 	account := make(map[string]string)
@@ -124,9 +125,9 @@ func (m *mockClient) Links() map[string]map[string]string {
 	self["href"] = m.rootURL
 	mockLinks["self"] = self
 	mockLinks["account"] = account
-	return mockLinks
+	return mockLinks, nil
 }
-func (m *mockClient) SetAccessToken() error {
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
 	return nil
 }
 
@@ -134,6 +135,11 @@ func (m *mockClient) SetRootURL(url string) {
 	m.rootURL = url
 }
 
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
 func stubClient() *mockClient {
 	mock := &mockClient{
 		Env:          "Test",
@@ -162,7 +168,7 @@ func TestGetFundingSource(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -176,12 +182,12 @@ func TestUpdateFundingSource(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
 	fundingSource.Name = "Bank of Brgle"
-	err = fundingSource.Update()
+	err = fundingSource.Update(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -194,7 +200,7 @@ func TestInitiateMicroDeposits(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -204,7 +210,7 @@ func TestInitiateMicroDeposits(t *testing.T) {
 	}))
 	defer ts.Close()
 	fundingSource.Client.SetRootURL(ts.URL)
-	err = fundingSource.IntiateMicroDeposits()
+	err = fundingSource.IntiateMicroDeposits(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -217,7 +223,7 @@ func TestVerifyMicroDeposits(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -232,7 +238,7 @@ func TestVerifyMicroDeposits(t *testing.T) {
 			Currency: "USD",
 		},
 	}
-	err = fundingSource.VerifyMicroDeposits(vr)
+	err = fundingSource.VerifyMicroDeposits(context.Background(), vr)
 	if err != nil {
 		t.Error(err)
 	}
@@ -245,7 +251,7 @@ func TestGetBalance(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -254,7 +260,7 @@ func TestGetBalance(t *testing.T) {
 	}))
 	defer ts.Close()
 	fundingSource.Client.SetRootURL(ts.URL)
-	balance, err := fundingSource.GetBalance()
+	balance, err := fundingSource.GetBalance(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -267,7 +273,7 @@ func TestGetMicroDepositsDetails(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
@@ -276,9 +282,9 @@ func TestGetMicroDepositsDetails(t *testing.T) {
 	}))
 	defer ts.Close()
 	fundingSource.Client.SetRootURL(ts.URL)
-	details, err := fundingSource.GetMicroDepositsDetails()
+	details, err := fundingSource.GetMicroDepositsDetails(context.Background())
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	t.Log("Micro-deposit status = ", details.Status)
 }
@@ -290,11 +296,11 @@ func TestRemove(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	fundingSource, err := GetFundingSource(mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
+	fundingSource, err := GetFundingSource(context.Background(), mock, "49dbaa24-1580-4b1c-8b58-24e26656fa31")
 	if err != nil {
 		t.Error(err)
 	}
-	err = fundingSource.Remove()
+	err = fundingSource.Remove(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
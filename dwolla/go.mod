@@ -1,9 +1,9 @@
 module github.com/ahmedaabouzied/dwolla-go/dwolla
 
-go 1.12
+go 1.13
 
 require (
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.3.0 // indirect
 	github.com/stripe/stripe-go v61.21.0+incompatible // indirect
 	github.com/subosito/gotenv v1.1.1
@@ -1,7 +1,11 @@
 // Package masspayment provides methods to use mass payments via the dwolla api.
 package masspayment
 
-import "github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+import (
+	"context"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+)
 
 // MassPayment represents a mass payment on dwolla api
 type MassPayment struct {
@@ -16,6 +20,66 @@ type MassPayment struct {
 // ListMassPaymentsResponse is the response that is returned by dwolla
 // to list mass payments
 type ListMassPaymentsResponse struct {
-	Links    map[string]client.Link   `json:"_links"`
+	client.Page
 	Embedded map[string][]MassPayment `json:"_embedded"`
 }
+
+// Iterator iterates over the mass payments of a list,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []MassPayment
+}
+
+// GetMassPayment retrieves a mass payment by ID.
+func GetMassPayment(ctx context.Context, c client.DwollaClient, massPaymentID string) (*MassPayment, error) {
+	body := &MassPayment{}
+	_, err := client.Do(ctx, c, "GET", "/mass-payments/"+massPaymentID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// ListPage retrieves the page of mass payments at hrefOrPath.
+// It returns the mass payments of the page and its pagination fields.
+func ListPage(ctx context.Context, c client.DwollaClient, hrefOrPath string) ([]MassPayment, client.Page, error) {
+	body := &ListMassPaymentsResponse{}
+	_, err := client.Do(ctx, c, "GET", hrefOrPath, nil, body)
+	if err != nil {
+		return nil, client.Page{}, err
+	}
+	return body.Embedded["mass-payments"], body.Page, nil
+}
+
+// NewIterator returns an iterator over the mass payments listed at hrefOrPath.
+func NewIterator(ctx context.Context, c client.DwollaClient, hrefOrPath string) *Iterator {
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, hrefOrPath, func(ctx context.Context, href string) (client.Page, int, error) {
+		payments, page, err := ListPage(ctx, c, href)
+		it.items = payments
+		return page, len(payments), err
+	})
+	return it
+}
+
+// Next advances to the next mass payment. It returns false when
+// there are no more mass payments or a request failed.
+func (it *Iterator) Next() bool {
+	return it.pages.Next()
+}
+
+// MassPayment returns the current mass payment.
+func (it *Iterator) MassPayment() *MassPayment {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of mass payments in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
+}
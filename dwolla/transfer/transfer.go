@@ -2,20 +2,15 @@
 package transfer
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"net/http"
-	"os"
+	"context"
 
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
 	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
-	"github.com/pkg/errors"
 )
 
 // Transfer has the fields to make a transfer between two funding sources.
 type Transfer struct {
-	Client        client.DwollaClient
+	Client        client.DwollaClient    `json:"-"`
 	ID            string                 `json:"id"`
 	Links         map[string]client.Link `json:"_links"`
 	Amount        *funding.Amount        `json:"amount"`
@@ -35,8 +30,15 @@ type Fees struct {
 
 // ListTransferResponse is the response for list transfers end point.
 type ListTransferResponse struct {
-	Links    map[string]client.Link `json:"_links"`
-	Embedded map[string][]Transfer  `json:"_embedded"`
+	client.Page
+	Embedded map[string][]Transfer `json:"_embedded"`
+}
+
+// Iterator iterates over the transfers of a list,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []Transfer
 }
 
 // OnDemandAuthResponse is the response for the on-demand-authorization endpoint.
@@ -46,185 +48,110 @@ type OnDemandAuthResponse struct {
 	ButtonText string                 `json:"buttonText"`
 }
 
-// CreateTransfer initiates a new transfer between two funding sources.
-func CreateTransfer(c client.DwollaClient, transfer *Transfer) error {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(transfer)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling the json body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/transfers", bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+// CreateTransfer initiates a new transfer between two funding sources
+// and returns the ID and href of the new transfer.
+func CreateTransfer(ctx context.Context, c client.DwollaClient, transfer *Transfer) (*client.Created, error) {
+	return client.Create(ctx, c, "/transfers", transfer)
+}
+
+// CreateAndGetTransfer initiates a new transfer like CreateTransfer,
+// then retrieves the created transfer.
+func CreateAndGetTransfer(ctx context.Context, c client.DwollaClient, transfer *Transfer) (*Transfer, error) {
+	created, err := CreateTransfer(ctx, c, transfer)
 	if err != nil {
-		return errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 201:
-		return nil
-	case 401:
-		return errors.New("invalid access token")
-	case 400:
-		io.Copy(os.Stdout, res.Body)
-		return errors.New(res.Status)
-	case 403:
-		return errors.New("Not authorized to create a transfer")
-	case 404:
-		return errors.New("not found")
-	default:
-		return errors.New(res.Status)
+		return nil, err
 	}
+	return GetTransfer(ctx, c, created.ID)
 }
 
 // GetTransfer retrieves a transaction
-func GetTransfer(c client.DwollaClient, transferID string) (*Transfer, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/transfers/"+transferID, nil)
+func GetTransfer(ctx context.Context, c client.DwollaClient, transferID string) (*Transfer, error) {
+	body := &Transfer{}
+	_, err := client.Do(ctx, c, "GET", "/transfers/"+transferID, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
+		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
+	body.Client = c
+	return body, nil
+}
+
+// ListPage retrieves the page of transfers at hrefOrPath.
+// It returns the transfers of the page and its pagination fields.
+func ListPage(ctx context.Context, c client.DwollaClient, hrefOrPath string) ([]Transfer, client.Page, error) {
+	body := &ListTransferResponse{}
+	_, err := client.Do(ctx, c, "GET", hrefOrPath, nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
+		return nil, client.Page{}, err
 	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Transfer{}
-		err = d.Decode(body)
-		body.Client = c
-		return body, nil
-	case 404:
-		return nil, errors.New("transfer not found")
-	default:
-		return nil, errors.New(res.Status)
+	transfers := body.Embedded["transfers"]
+	for i := range transfers {
+		transfers[i].Client = c
 	}
+	return transfers, body.Page, nil
+}
+
+// NewIterator returns an iterator over the transfers listed at hrefOrPath.
+func NewIterator(ctx context.Context, c client.DwollaClient, hrefOrPath string) *Iterator {
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, hrefOrPath, func(ctx context.Context, href string) (client.Page, int, error) {
+		transfers, page, err := ListPage(ctx, c, href)
+		it.items = transfers
+		return page, len(transfers), err
+	})
+	return it
+}
+
+// Next advances to the next transfer. It returns false when
+// there are no more transfers or a request failed.
+func (it *Iterator) Next() bool {
+	return it.pages.Next()
+}
+
+// Transfer returns the current transfer.
+func (it *Iterator) Transfer() *Transfer {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of transfers in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
 }
 
 // ListFees retrieves a list of the fees of the transfer
-func (t *Transfer) ListFees() (*Fees, error) {
-	var c = t.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/transfers/"+t.ID+"/fees", nil)
+func (t *Transfer) ListFees(ctx context.Context) (*Fees, error) {
+	body := &Fees{}
+	_, err := client.Do(ctx, t.Client, "GET", "/transfers/"+t.ID+"/fees", nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Fees{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		return body, nil
-	case 404:
-		return nil, errors.New("transfer not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	return body, nil
 }
 
 // Failure retrieves the failure reassons of a transfer.
-func (t *Transfer) Failure() (*client.DwollaError, error) {
-	var c = t.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+func (t *Transfer) Failure(ctx context.Context) (*client.DwollaError, error) {
+	body := &client.DwollaError{}
+	_, err := client.Do(ctx, t.Client, "GET", "/transfers/"+t.ID+"/failure", nil, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("GET", c.RootURL()+"/transfers"+t.ID+"/failure", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &client.DwollaError{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		return body, nil
-	case 404:
-		return nil, errors.New("transfer not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	return body, nil
 }
 
 // Cancel a transfer.
-func (t *Transfer) Cancel() (*Transfer, error) {
-	var c = t.Client
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+func (t *Transfer) Cancel(ctx context.Context) (*Transfer, error) {
+	req := map[string]string{"status": "cancelled"}
+	body := &Transfer{}
+	_, err := client.Do(ctx, t.Client, "POST", "/transfers/"+t.ID, req, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth token")
-	}
-	body, err := json.Marshal(`{"status" : "canceled"`)
-	if err != nil {
-		return nil, errors.Wrap(err, "error marshalling json requset body")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/transfers/"+t.ID, bytes.NewReader(body))
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &Transfer{}
-		err = d.Decode(body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing JSON response")
-		}
-		return body, nil
-	case 404:
-		return nil, errors.New("transfer not found")
-	default:
-		return nil, errors.New(res.Status)
+		return nil, err
 	}
+	body.Client = t.Client
+	return body, nil
 }
 
 // CreateOnDemandAuth create an on-demand bank transfer authorization for your Customer.
@@ -232,36 +159,11 @@ func (t *Transfer) Cancel() (*Transfer, error) {
 // from their bank account using ACH at a later point in time for products or services delivered.
 // This on-demand authorization is supplied along with the Customer’s bank details when creating
 // a new Customer funding source.
-func CreateOnDemandAuth(c client.DwollaClient) (string, error) {
-	hc := &http.Client{}
-	token, err := c.AuthToken()
+func CreateOnDemandAuth(ctx context.Context, c client.DwollaClient) (string, error) {
+	body := &OnDemandAuthResponse{}
+	_, err := client.Do(ctx, c, "POST", "/on-demand-authorizations", nil, body)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get auth token")
-	}
-	req, err := http.NewRequest("POST", c.RootURL()+"/on-demand-authorization", nil)
-	if err != nil {
-		return "", errors.Wrap(err, "error creating the request")
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Accept", "application/vnd.dwolla.v1.hal+json")
-	req.Header.Add("Content-Type", "application/vnd.dwolla.v1.hal+json")
-	res, err := hc.Do(req)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to make request to dwolla api")
-	}
-	defer res.Body.Close()
-	switch res.StatusCode {
-	case 200:
-		d := json.NewDecoder(res.Body)
-		body := &OnDemandAuthResponse{}
-		err = d.Decode(body)
-		if err != nil {
-			return "", errors.Wrap(err, "error parsing JSON response")
-		}
-		return body.Links["self"].Href, nil
-	case 404:
-		return "", errors.New("transfer not found")
-	default:
-		return "", errors.New(res.Status)
+		return "", err
 	}
+	return body.Links["self"].Href, nil
 }
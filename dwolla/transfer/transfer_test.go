@@ -1,6 +1,7 @@
 package transfer
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -204,7 +205,7 @@ type mockClient struct {
 func (m *mockClient) RootURL() string {
 	return m.rootURL
 }
-func (m *mockClient) Root() (map[string]map[string]string, error) {
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	account := make(map[string]string)
 	self := make(map[string]string)
@@ -215,10 +216,10 @@ func (m *mockClient) Root() (map[string]map[string]string, error) {
 	return mockLinks, nil
 }
 
-func (m *mockClient) AuthToken() (string, error) {
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
 	return m.authToken, nil
 }
-func (m *mockClient) Links() map[string]map[string]string {
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
 	mockLinks := make(map[string]map[string]string)
 	self := make(map[string]string)
 	account := make(map[string]string)
@@ -226,9 +227,9 @@ func (m *mockClient) Links() map[string]map[string]string {
 	self["href"] = m.rootURL
 	mockLinks["self"] = self
 	mockLinks["account"] = account
-	return mockLinks
+	return mockLinks, nil
 }
-func (m *mockClient) SetAccessToken() error {
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
 	return nil
 }
 
@@ -236,6 +237,11 @@ func (m *mockClient) SetRootURL(url string) {
 	m.rootURL = url
 }
 
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
 func stubClient() *mockClient {
 	mock := &mockClient{
 		Env:          "Test",
@@ -259,6 +265,7 @@ func stubClient() *mockClient {
 }
 func TestCreateTransfer(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8")
 		w.WriteHeader(201)
 		fmt.Fprint(w, "Created")
 	}))
@@ -280,9 +287,36 @@ func TestCreateTransfer(t *testing.T) {
 		Amount: amount,
 		Links:  links,
 	}
-	err := CreateTransfer(mock, tr)
+	created, err := CreateTransfer(context.Background(), mock, tr)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if created.ID != "15c6bcce-46f7-e811-8112-e8dd3bececa8" || created.Href != "https://api-sandbox.dwolla.com/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8" {
+		t.Errorf("unexpected created transfer %+v", created)
+	}
+}
+
+func TestCreateAndGetTransfer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Header().Set("Location", "http://"+r.Host+"/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8")
+			w.WriteHeader(201)
+			return
+		}
+		if r.URL.Path != "/transfers/15c6bcce-46f7-e811-8112-e8dd3bececa8" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, mockTransfer)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	tr, err := CreateAndGetTransfer(context.Background(), mock, &Transfer{Amount: &funding.Amount{Value: "300", Currency: "USD"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.ID == "" || tr.Client == nil {
+		t.Errorf("expected a populated transfer, got %+v", tr)
 	}
 }
 
@@ -293,7 +327,7 @@ func TestGetTransfer(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	transfer, err := GetTransfer(mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
+	transfer, err := GetTransfer(context.Background(), mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
 	if err != nil {
 		t.Error(err)
 	}
@@ -307,7 +341,7 @@ func TestListFees(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	transfer, err := GetTransfer(mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
+	transfer, err := GetTransfer(context.Background(), mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
 	if err != nil {
 		t.Error(err)
 	}
@@ -316,7 +350,7 @@ func TestListFees(t *testing.T) {
 	}))
 	defer ts.Close()
 	transfer.Client.SetRootURL(ts.URL)
-	fees, err := transfer.ListFees()
+	fees, err := transfer.ListFees(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -330,7 +364,7 @@ func TestFailure(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	transfer, err := GetTransfer(mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
+	transfer, err := GetTransfer(context.Background(), mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
 	if err != nil {
 		t.Error(err)
 	}
@@ -339,7 +373,7 @@ func TestFailure(t *testing.T) {
 	}))
 	defer ts.Close()
 	transfer.Client.SetRootURL(ts.URL)
-	fail, err := transfer.Failure()
+	fail, err := transfer.Failure(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -353,7 +387,7 @@ func TestCancel(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	transfer, err := GetTransfer(mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
+	transfer, err := GetTransfer(context.Background(), mock, "15c6bcce-46f7-e811-8112-e8dd3bececa8")
 	if err != nil {
 		t.Error(err)
 	}
@@ -362,7 +396,7 @@ func TestCancel(t *testing.T) {
 	}))
 	defer ts.Close()
 	transfer.Client.SetRootURL(ts.URL)
-	canceled, err := transfer.Cancel()
+	canceled, err := transfer.Cancel(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -376,7 +410,7 @@ func TestCreateOnDemand(t *testing.T) {
 	defer ts.Close()
 	mock := stubClient()
 	mock.SetRootURL(ts.URL)
-	link, err := CreateOnDemandAuth(mock)
+	link, err := CreateOnDemandAuth(context.Background(), mock)
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/pkg/errors"
+)
+
+// SignatureHeader is the header that carries the signature of a webhook.
+const SignatureHeader = "X-Request-Signature-SHA-256"
+
+// DefaultMaxBodySize is the size above which a webhook body is rejected,
+// unless the WithMaxBodySize option is given.
+const DefaultMaxBodySize = 1 << 20
+
+// HandlerFunc handles an event delivered by a webhook.
+// Returning an error makes dwolla deliver the webhook again later.
+type HandlerFunc func(ctx context.Context, evt *events.Event) error
+
+// HandlerOption configures a Handler created by NewHandler.
+type HandlerOption func(*Handler)
+
+// Handler is an http.Handler that verifies the signature of the webhooks
+// it receives and calls the function registered for the topic of each event.
+//
+// It responds with:
+//   - 200 when the event was handled, was already handled, or no function is registered for its topic
+//   - 400 when the body is not a valid event
+//   - 401 when the signature is missing or invalid
+//   - 405 for methods other than POST
+//   - 413 when the body exceeds the maximum size
+//   - 500 when the registered function returned an error, so that dwolla retries
+//
+// Dwolla delivers a webhook at least once. With the WithSeenStore option,
+// the events that were already handled are acknowledged without calling
+// the registered functions again.
+//
+// A Handler is safe for concurrent use by multiple goroutines.
+type Handler struct {
+	secret      []byte
+	maxBodySize int64
+	onError     func(*http.Request, error)
+	client      client.DwollaClient
+	seen        SeenStore
+	mu          sync.RWMutex
+	topics      map[events.Topic]HandlerFunc
+	fallback    HandlerFunc
+	flightMu    sync.Mutex
+	inflight    map[string]*dispatchCall // Events being dispatched, by ID
+}
+
+// dispatchCall is an event being dispatched. done is closed once err is set.
+type dispatchCall struct {
+	done chan struct{}
+	err  error
+}
+
+// NewHandler returns a Handler that verifies webhooks with the secret
+// of the webhook subscription.
+func NewHandler(secret string, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		secret:      []byte(secret),
+		maxBodySize: DefaultMaxBodySize,
+		topics:      make(map[events.Topic]HandlerFunc),
+		inflight:    make(map[string]*dispatchCall),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithMaxBodySize sets the size in bytes above which a webhook body is rejected.
+func WithMaxBodySize(n int64) HandlerOption {
+	return func(h *Handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithErrorHandler sets a function called with every webhook that is rejected
+// or whose handler failed, together with the reason.
+func WithErrorHandler(fn func(r *http.Request, err error)) HandlerOption {
+	return func(h *Handler) {
+		h.onError = fn
+	}
+}
+
+// WithSeenStore sets the store of the handled events. An event found in s
+// is acknowledged without calling the registered function, and an event
+// delivered again while it is being handled waits for the first delivery.
+func WithSeenStore(s SeenStore) HandlerOption {
+	return func(h *Handler) {
+		h.seen = s
+	}
+}
+
+// On registers fn to handle the events of topic, such as events.TopicTransferCompleted.
+// It replaces the function previously registered for topic, if any.
+func (h *Handler) On(topic events.Topic, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.topics[topic] = fn
+}
+
+// Default registers fn to handle the events of topics that have no registered function.
+func (h *Handler) Default(fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = fn
+}
+
+// Dispatch calls the function registered for the topic of evt.
+// Events of topics without a registered function are ignored, as are
+// the events found in the seen store when one is set.
+func (h *Handler) Dispatch(ctx context.Context, evt *events.Event) error {
+	if h.seen == nil {
+		return h.dispatch(ctx, evt)
+	}
+	h.flightMu.Lock()
+	if call, ok := h.inflight[evt.ID]; ok {
+		h.flightMu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &dispatchCall{done: make(chan struct{})}
+	h.inflight[evt.ID] = call
+	h.flightMu.Unlock()
+
+	call.err = h.dispatchOnce(ctx, evt)
+	h.flightMu.Lock()
+	delete(h.inflight, evt.ID)
+	h.flightMu.Unlock()
+	close(call.done)
+	return call.err
+}
+
+// dispatchOnce dispatches evt unless it is in the seen store,
+// and adds it to the store once handled.
+func (h *Handler) dispatchOnce(ctx context.Context, evt *events.Event) error {
+	seen, err := h.seen.Seen(ctx, evt.ID)
+	if err != nil {
+		return errors.Wrap(err, "error checking seen events")
+	}
+	if seen {
+		return nil
+	}
+	err = h.dispatch(ctx, evt)
+	if err != nil {
+		return err
+	}
+	err = h.seen.MarkSeen(ctx, evt.ID)
+	if err != nil {
+		return errors.Wrap(err, "error marking event as seen")
+	}
+	return nil
+}
+
+func (h *Handler) dispatch(ctx context.Context, evt *events.Event) error {
+	h.mu.RLock()
+	fn, ok := h.topics[evt.Topic]
+	if !ok {
+		fn = h.fallback
+	}
+	h.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, evt)
+}
+
+// ServeHTTP verifies and decodes a webhook, then dispatches its event.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, errors.Errorf("unexpected method %s", r.Method))
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, h.maxBodySize+1))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, errors.Wrap(err, "error reading webhook body"))
+		return
+	}
+	if int64(len(body)) > h.maxBodySize {
+		h.fail(w, r, http.StatusRequestEntityTooLarge, errors.New("webhook body is too large"))
+		return
+	}
+	if !VerifySignature(string(h.secret), body, r.Header.Get(SignatureHeader)) {
+		h.fail(w, r, http.StatusUnauthorized, errors.New("invalid webhook signature"))
+		return
+	}
+	evt := &events.Event{}
+	err = json.Unmarshal(body, evt)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, errors.Wrap(err, "error parsing webhook body"))
+		return
+	}
+	if evt.ID == "" || evt.Topic == "" {
+		h.fail(w, r, http.StatusBadRequest, errors.New("webhook event has no id or topic"))
+		return
+	}
+	err = h.Dispatch(r.Context(), evt)
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, errors.Wrapf(err, "error handling event %s", evt.ID))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.onError != nil {
+		h.onError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// Sign returns the signature dwolla sends with a webhook body:
+// the hex encoded HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is the signature of body
+// with secret. The comparison takes constant time.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) != sha256.Size {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
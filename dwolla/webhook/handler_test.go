@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+)
+
+var mockEvent = `
+{
+  "id": "80d8ff4d-2a63-4b3b-8a5a-0a27c3e2b1f5",
+  "resourceId": "d0d5c0d3-e2b1-e811-8112-e8dd3bececa8",
+  "topic": "transfer_completed",
+  "timestamp": "2018-11-06T15:04:05.000Z",
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/events/80d8ff4d-2a63-4b3b-8a5a-0a27c3e2b1f5"
+    },
+    "account": {
+      "href": "https://api-sandbox.dwolla.com/accounts/ca32853c-48fa-40be-ae75-77b37504581b"
+    },
+    "resource": {
+      "href": "https://api-sandbox.dwolla.com/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8"
+    },
+    "customer": {
+      "href": "https://api-sandbox.dwolla.com/customers/fc451a7a-ae30-4404-ab95-e3553fcd733f"
+    }
+  },
+  "created": "2018-11-06T15:04:05.000Z"
+}
+`
+
+const mockSecret = "your webhook secret"
+
+func signedRequest(body, secret string) *http.Request {
+	req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, Sign(secret, []byte(body)))
+	return req
+}
+
+func TestHandlerDispatch(t *testing.T) {
+	h := NewHandler(mockSecret)
+	var got *events.Event
+	h.On("transfer_completed", func(ctx context.Context, evt *events.Event) error {
+		got = evt
+		return nil
+	})
+	h.On("customer_created", func(ctx context.Context, evt *events.Event) error {
+		t.Error("unexpected call for another topic")
+		return nil
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(mockEvent, mockSecret))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got == nil || got.ResourceID != "d0d5c0d3-e2b1-e811-8112-e8dd3bececa8" || got.ResourceHref() == "" {
+		t.Errorf("unexpected event %+v", got)
+	}
+}
+
+func TestHandlerStatusCodes(t *testing.T) {
+	h := NewHandler(mockSecret, WithMaxBodySize(2048))
+	h.On("transfer_completed", func(ctx context.Context, evt *events.Event) error {
+		return errors.New("database is down")
+	})
+	unsigned := httptest.NewRequest("POST", "/webhooks", strings.NewReader(mockEvent))
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+	}{
+		{"handler error", signedRequest(mockEvent, mockSecret), http.StatusInternalServerError},
+		{"wrong secret", signedRequest(mockEvent, "another secret"), http.StatusUnauthorized},
+		{"missing signature", unsigned, http.StatusUnauthorized},
+		{"malformed body", signedRequest("{not json", mockSecret), http.StatusBadRequest},
+		{"missing topic", signedRequest(`{"id": "abc"}`, mockSecret), http.StatusBadRequest},
+		{"oversized body", signedRequest(strings.Repeat(" ", 4096)+mockEvent, mockSecret), http.StatusRequestEntityTooLarge},
+		{"unhandled topic", signedRequest(`{"id": "abc", "topic": "customer_created"}`, mockSecret), http.StatusOK},
+		{"wrong method", httptest.NewRequest("GET", "/webhooks", nil), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, tt.req)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.code, w.Code)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(mockEvent)
+	sig := Sign(mockSecret, body)
+	if !VerifySignature(mockSecret, body, sig) {
+		t.Error("expected the signature to be valid")
+	}
+	if VerifySignature(mockSecret, bytes.ToUpper(body), sig) {
+		t.Error("expected the signature of another body to be invalid")
+	}
+	if VerifySignature(mockSecret, body, "not hex") {
+		t.Error("expected a malformed signature to be invalid")
+	}
+}
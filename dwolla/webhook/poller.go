@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/pkg/errors"
+)
+
+// DefaultPollInterval is the time between two reads of the events feed,
+// unless the WithPollInterval option is given.
+const DefaultPollInterval = time.Minute
+
+// Dispatcher handles events. *Handler is a Dispatcher.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, evt *events.Event) error
+}
+
+// Checkpoint stores the ID of the newest event processed by a Poller,
+// so that polling resumes where it stopped after a restart.
+type Checkpoint interface {
+	// Load returns the stored event ID, or an empty string when none was saved.
+	Load(ctx context.Context) (string, error)
+	// Save stores eventID, replacing the stored one.
+	Save(ctx context.Context, eventID string) error
+}
+
+// MemoryCheckpoint is a Checkpoint kept in memory.
+// Its zero value is ready to use.
+type MemoryCheckpoint struct {
+	mu sync.Mutex
+	id string
+}
+
+// Load implements Checkpoint.
+func (m *MemoryCheckpoint) Load(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.id, nil
+}
+
+// Save implements Checkpoint.
+func (m *MemoryCheckpoint) Save(ctx context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.id = eventID
+	return nil
+}
+
+// FileCheckpoint is a Checkpoint stored in the file at Path.
+// The file is replaced atomically on every save.
+type FileCheckpoint struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Load implements Checkpoint.
+func (f *FileCheckpoint) Load(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "error reading checkpoint file")
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save implements Checkpoint.
+func (f *FileCheckpoint) Save(ctx context.Context, eventID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp := f.Path + ".tmp"
+	err := ioutil.WriteFile(tmp, []byte(eventID+"\n"), 0600)
+	if err != nil {
+		return errors.Wrap(err, "error writing checkpoint file")
+	}
+	err = os.Rename(tmp, f.Path)
+	if err != nil {
+		return errors.Wrap(err, "error replacing checkpoint file")
+	}
+	return nil
+}
+
+// PollerOption configures a Poller created by NewPoller.
+type PollerOption func(*Poller)
+
+// Poller periodically reads the events feed of the account and dispatches
+// every event that is newer than its checkpoint, oldest first. It catches up
+// on the events whose webhooks were missed, for instance while the webhook
+// endpoint was down.
+//
+// The checkpoint only moves past an event once it was dispatched without error,
+// so a failed event is dispatched again on the next poll. An event can be
+// dispatched both by a Poller and by a Handler receiving its webhook.
+// Using a Handler created with WithSeenStore as the Dispatcher of the Poller
+// handles such an event once.
+type Poller struct {
+	client     client.DwollaClient
+	dispatcher Dispatcher
+	checkpoint Checkpoint
+	interval   time.Duration
+	lookback   time.Duration
+	onError    func(error)
+	mu         sync.Mutex // Serializes polls
+	started    bool       // Set after the first successful poll
+}
+
+// NewPoller returns a Poller that dispatches the events of the account of c to d.
+func NewPoller(c client.DwollaClient, d Dispatcher, cp Checkpoint, opts ...PollerOption) *Poller {
+	p := &Poller{
+		client:     c,
+		dispatcher: d,
+		checkpoint: cp,
+		interval:   DefaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithPollInterval sets the time between two reads of the events feed.
+// A d that is not positive is ignored and DefaultPollInterval is kept.
+func WithPollInterval(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		if d > 0 {
+			p.interval = d
+		}
+	}
+}
+
+// WithLookback makes the first poll without a saved checkpoint dispatch
+// the events created within d. By default the first poll only saves the
+// newest event as the checkpoint, without dispatching anything.
+// When the feed is empty on the first poll, every event added to it
+// afterwards is dispatched.
+func WithLookback(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.lookback = d
+	}
+}
+
+// WithPollErrorHandler sets a function called with the error of every failed poll.
+func WithPollErrorHandler(fn func(error)) PollerOption {
+	return func(p *Poller) {
+		p.onError = fn
+	}
+}
+
+// Run polls the events feed every interval until ctx is done.
+// It returns the error of ctx.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		err := p.Poll(ctx)
+		if err != nil && ctx.Err() == nil && p.onError != nil {
+			p.onError(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll reads the events feed once and dispatches the events newer than the checkpoint.
+func (p *Poller) Poll(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last, err := p.checkpoint.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error loading checkpoint")
+	}
+	var pending []events.Event
+	if last == "" && !p.started {
+		pending, err = p.firstEvents(ctx)
+	} else {
+		// Without a checkpoint after the first poll, the feed was empty
+		// until now and all of its events are new.
+		pending, err = p.eventsSince(ctx, last)
+	}
+	if err != nil {
+		return err
+	}
+	p.started = true
+	for i := len(pending) - 1; i >= 0; i-- {
+		evt := &pending[i]
+		err = p.dispatcher.Dispatch(ctx, evt)
+		if err != nil {
+			return errors.Wrapf(err, "error handling event %s", evt.ID)
+		}
+		err = p.checkpoint.Save(ctx, evt.ID)
+		if err != nil {
+			return errors.Wrap(err, "error saving checkpoint")
+		}
+	}
+	return nil
+}
+
+// eventsSince returns the events newer than the event with ID last, newest first.
+func (p *Poller) eventsSince(ctx context.Context, last string) ([]events.Event, error) {
+	var pending []events.Event
+	it := events.IterUntil(ctx, p.client, last, &client.ListOptions{Limit: 200})
+	for it.Next() {
+		pending = append(pending, *it.Event())
+	}
+	if err := it.Err(); err != nil {
+		return nil, errors.Wrap(err, "error listing events")
+	}
+	return pending, nil
+}
+
+// firstEvents returns the events to dispatch when there is no checkpoint yet.
+// Without a lookback, the newest event is saved as the checkpoint instead.
+func (p *Poller) firstEvents(ctx context.Context) ([]events.Event, error) {
+	if p.lookback <= 0 {
+		evts, _, err := events.List(ctx, p.client, &client.ListOptions{Limit: 1})
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing events")
+		}
+		if len(evts) > 0 {
+			err = p.checkpoint.Save(ctx, evts[0].ID)
+			if err != nil {
+				return nil, errors.Wrap(err, "error saving checkpoint")
+			}
+		}
+		return nil, nil
+	}
+	since := time.Now().Add(-p.lookback)
+	var pending []events.Event
+	it := events.Iter(ctx, p.client, &client.ListOptions{Limit: 200})
+	for it.Next() && it.Event().CreatedAt.After(since) {
+		pending = append(pending, *it.Event())
+	}
+	if err := it.Err(); err != nil {
+		return nil, errors.Wrap(err, "error listing events")
+	}
+	return pending, nil
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+)
+
+// feed is a fake events feed, newest event first.
+type feed struct {
+	mu   sync.Mutex
+	evts []string
+}
+
+func (f *feed) add(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evts = append([]string{id}, f.evts...)
+}
+
+func (f *feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var items []string
+	for _, id := range f.evts {
+		created := time.Now().Add(-time.Minute).Format(time.RFC3339)
+		items = append(items, fmt.Sprintf(`{"id": %q, "topic": "transfer_failed", "created": %q}`, id, created))
+	}
+	fmt.Fprintf(w, `{"_links": {}, "_embedded": {"events": [%s]}, "total": %d}`, strings.Join(items, ","), len(items))
+}
+
+type recorder struct {
+	mu   sync.Mutex
+	ids  []string
+	fail string
+}
+
+func (r *recorder) Dispatch(ctx context.Context, evt *events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if evt.ID == r.fail {
+		return errors.New("handler failed")
+	}
+	r.ids = append(r.ids, evt.ID)
+	return nil
+}
+
+func TestPoll(t *testing.T) {
+	f := &feed{}
+	f.add("event-1")
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	rec := &recorder{}
+	cp := &MemoryCheckpoint{}
+	p := NewPoller(mock, rec, cp)
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := cp.Load(context.Background()); id != "event-1" || len(rec.ids) != 0 {
+		t.Errorf("expected the first poll to only save the checkpoint, got %q and %v", id, rec.ids)
+	}
+
+	f.add("event-2")
+	f.add("event-3")
+	f.add("event-4")
+	rec.fail = "event-3"
+	if err := p.Poll(context.Background()); err == nil {
+		t.Error("expected the failed event to be reported")
+	}
+	if id, _ := cp.Load(context.Background()); id != "event-2" {
+		t.Errorf("expected the checkpoint to stop before the failed event, got %q", id)
+	}
+	rec.fail = ""
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rec.ids) != "[event-2 event-3 event-4]" {
+		t.Errorf("expected each event to be dispatched once, oldest first, got %v", rec.ids)
+	}
+}
+
+func TestPollLookback(t *testing.T) {
+	f := &feed{}
+	f.add("event-1")
+	f.add("event-2")
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	rec := &recorder{}
+	p := NewPoller(mock, rec, &MemoryCheckpoint{}, WithLookback(time.Hour))
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rec.ids) != "[event-1 event-2]" {
+		t.Errorf("expected the recent events to be dispatched, got %v", rec.ids)
+	}
+}
+
+func TestPollEmptyFeed(t *testing.T) {
+	f := &feed{}
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	rec := &recorder{}
+	cp := &MemoryCheckpoint{}
+	p := NewPoller(mock, rec, cp)
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	f.add("transfer-failed-1")
+	for i := 0; i < 2; i++ {
+		if err := p.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if id, _ := cp.Load(context.Background()); id != "transfer-failed-1" || fmt.Sprint(rec.ids) != "[transfer-failed-1]" {
+		t.Errorf("expected the first event after an empty feed to be dispatched once, got %q and %v", id, rec.ids)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ts := httptest.NewServer(&feed{})
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	p := NewPoller(mock, &recorder{}, &MemoryCheckpoint{}, WithPollInterval(time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline error, got %v", err)
+	}
+}
+
+func TestWithPollIntervalNotPositive(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		p := NewPoller(stubClient(), &recorder{}, &MemoryCheckpoint{}, WithPollInterval(d))
+		if p.interval != DefaultPollInterval {
+			t.Errorf("expected the default interval for %v, got %v", d, p.interval)
+		}
+	}
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cp := &FileCheckpoint{Path: filepath.Join(dir, "events")}
+	id, err := cp.Load(context.Background())
+	if err != nil || id != "" {
+		t.Fatalf("expected an empty checkpoint, got %q and %v", id, err)
+	}
+	if err := cp.Save(context.Background(), "event-1"); err != nil {
+		t.Fatal(err)
+	}
+	id, err = (&FileCheckpoint{Path: cp.Path}).Load(context.Background())
+	if err != nil || id != "event-1" {
+		t.Errorf("expected the saved checkpoint, got %q and %v", id, err)
+	}
+}
@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"path"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/customer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/funding"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/masspayment"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/transfer"
+	"github.com/pkg/errors"
+)
+
+// Resolve retrieves the resource evt is about by following its resource link.
+// The result is a *customer.Customer, *transfer.Transfer, *funding.Resource,
+// *customer.Document or *masspayment.MassPayment, depending on the resource.
+func Resolve(ctx context.Context, c client.DwollaClient, evt *events.Event) (interface{}, error) {
+	href := evt.ResourceHref()
+	if href == "" {
+		return nil, errors.Errorf("event %s has no resource link", evt.ID)
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing resource link of event %s", evt.ID)
+	}
+	collection, id := path.Base(path.Dir(u.Path)), path.Base(u.Path)
+	// Each resource is assigned to res only without error, so that a failed
+	// retrieval returns a nil interface rather than a nil pointer in it.
+	var res interface{}
+	switch collection {
+	case "customers":
+		var cu *customer.Customer
+		if cu, err = customer.GetCustomer(ctx, c, id); err == nil {
+			res = cu
+		}
+	case "transfers":
+		var t *transfer.Transfer
+		if t, err = transfer.GetTransfer(ctx, c, id); err == nil {
+			res = t
+		}
+	case "funding-sources":
+		var fs *funding.Resource
+		if fs, err = funding.GetFundingSource(ctx, c, id); err == nil {
+			res = fs
+		}
+	case "documents":
+		var doc *customer.Document
+		if doc, err = customer.GetDocument(ctx, c, id); err == nil {
+			res = doc
+		}
+	case "mass-payments":
+		var mp *masspayment.MassPayment
+		if mp, err = masspayment.GetMassPayment(ctx, c, id); err == nil {
+			res = mp
+		}
+	default:
+		return nil, errors.Errorf("unsupported resource %s of event %s", href, evt.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WithClient sets the client used to retrieve the resources of events
+// for the functions registered with the typed methods, such as OnTransfer.
+func WithClient(c client.DwollaClient) HandlerOption {
+	return func(h *Handler) {
+		h.client = c
+	}
+}
+
+// OnCustomer registers fn to handle the events of topic,
+// called with the customer the event is about.
+func (h *Handler) OnCustomer(topic events.Topic, fn func(ctx context.Context, evt *events.Event, cu *customer.Customer) error) {
+	h.On(topic, func(ctx context.Context, evt *events.Event) error {
+		res, err := h.resolve(ctx, evt)
+		if err != nil {
+			return err
+		}
+		cu, ok := res.(*customer.Customer)
+		if !ok {
+			return errors.Errorf("resource of event %s is not a customer", evt.ID)
+		}
+		return fn(ctx, evt, cu)
+	})
+}
+
+// OnTransfer registers fn to handle the events of topic,
+// called with the transfer the event is about.
+func (h *Handler) OnTransfer(topic events.Topic, fn func(ctx context.Context, evt *events.Event, t *transfer.Transfer) error) {
+	h.On(topic, func(ctx context.Context, evt *events.Event) error {
+		res, err := h.resolve(ctx, evt)
+		if err != nil {
+			return err
+		}
+		t, ok := res.(*transfer.Transfer)
+		if !ok {
+			return errors.Errorf("resource of event %s is not a transfer", evt.ID)
+		}
+		return fn(ctx, evt, t)
+	})
+}
+
+// OnFundingSource registers fn to handle the events of topic,
+// called with the funding source the event is about.
+func (h *Handler) OnFundingSource(topic events.Topic, fn func(ctx context.Context, evt *events.Event, f *funding.Resource) error) {
+	h.On(topic, func(ctx context.Context, evt *events.Event) error {
+		res, err := h.resolve(ctx, evt)
+		if err != nil {
+			return err
+		}
+		f, ok := res.(*funding.Resource)
+		if !ok {
+			return errors.Errorf("resource of event %s is not a funding source", evt.ID)
+		}
+		return fn(ctx, evt, f)
+	})
+}
+
+// OnDocument registers fn to handle the events of topic,
+// called with the document the event is about.
+func (h *Handler) OnDocument(topic events.Topic, fn func(ctx context.Context, evt *events.Event, d *customer.Document) error) {
+	h.On(topic, func(ctx context.Context, evt *events.Event) error {
+		res, err := h.resolve(ctx, evt)
+		if err != nil {
+			return err
+		}
+		d, ok := res.(*customer.Document)
+		if !ok {
+			return errors.Errorf("resource of event %s is not a document", evt.ID)
+		}
+		return fn(ctx, evt, d)
+	})
+}
+
+// OnMassPayment registers fn to handle the events of topic,
+// called with the mass payment the event is about.
+func (h *Handler) OnMassPayment(topic events.Topic, fn func(ctx context.Context, evt *events.Event, mp *masspayment.MassPayment) error) {
+	h.On(topic, func(ctx context.Context, evt *events.Event) error {
+		res, err := h.resolve(ctx, evt)
+		if err != nil {
+			return err
+		}
+		mp, ok := res.(*masspayment.MassPayment)
+		if !ok {
+			return errors.Errorf("resource of event %s is not a mass payment", evt.ID)
+		}
+		return fn(ctx, evt, mp)
+	})
+}
+
+// OnCustomerCreated registers fn to handle the customer_created events.
+func (h *Handler) OnCustomerCreated(fn func(ctx context.Context, evt *events.Event, cu *customer.Customer) error) {
+	h.OnCustomer(events.TopicCustomerCreated, fn)
+}
+
+// OnCustomerVerified registers fn to handle the customer_verified events.
+func (h *Handler) OnCustomerVerified(fn func(ctx context.Context, evt *events.Event, cu *customer.Customer) error) {
+	h.OnCustomer(events.TopicCustomerVerified, fn)
+}
+
+// OnTransferCreated registers fn to handle the transfer_created events.
+func (h *Handler) OnTransferCreated(fn func(ctx context.Context, evt *events.Event, t *transfer.Transfer) error) {
+	h.OnTransfer(events.TopicTransferCreated, fn)
+}
+
+// OnTransferCompleted registers fn to handle the transfer_completed events.
+func (h *Handler) OnTransferCompleted(fn func(ctx context.Context, evt *events.Event, t *transfer.Transfer) error) {
+	h.OnTransfer(events.TopicTransferCompleted, fn)
+}
+
+// OnTransferFailed registers fn to handle the transfer_failed events.
+func (h *Handler) OnTransferFailed(fn func(ctx context.Context, evt *events.Event, t *transfer.Transfer) error) {
+	h.OnTransfer(events.TopicTransferFailed, fn)
+}
+
+// OnTransferCancelled registers fn to handle the transfer_cancelled events.
+func (h *Handler) OnTransferCancelled(fn func(ctx context.Context, evt *events.Event, t *transfer.Transfer) error) {
+	h.OnTransfer(events.TopicTransferCancelled, fn)
+}
+
+// OnFundingSourceAdded registers fn to handle the funding_source_added events.
+func (h *Handler) OnFundingSourceAdded(fn func(ctx context.Context, evt *events.Event, f *funding.Resource) error) {
+	h.OnFundingSource(events.TopicFundingSourceAdded, fn)
+}
+
+func (h *Handler) resolve(ctx context.Context, evt *events.Event) (interface{}, error) {
+	if h.client == nil {
+		return nil, errors.New("no client to retrieve the resource of the event, use the WithClient option")
+	}
+	return Resolve(ctx, h.client, evt)
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/customer"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/transfer"
+)
+
+var mockTransfer = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8"
+    }
+  },
+  "id": "d0d5c0d3-e2b1-e811-8112-e8dd3bececa8",
+  "status": "processed",
+  "amount": {
+    "value": "42.00",
+    "currency": "USD"
+  },
+  "created": "2018-11-06T15:04:05.000Z"
+}
+`
+
+func resourceServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/transfers/"):
+			fmt.Fprint(w, mockTransfer)
+		case strings.HasPrefix(r.URL.Path, "/customers/"):
+			fmt.Fprint(w, `{"id": "fc451a7a-ae30-4404-ab95-e3553fcd733f", "firstName": "Jane"}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+}
+
+func TestResolve(t *testing.T) {
+	ts := resourceServer(t)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"https://api-sandbox.dwolla.com/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8", "*transfer.Transfer"},
+		{"https://api-sandbox.dwolla.com/customers/fc451a7a-ae30-4404-ab95-e3553fcd733f", "*customer.Customer"},
+	}
+	for _, tt := range tests {
+		evt := &events.Event{ID: "abc", Links: map[string]client.Link{"resource": {Href: tt.href}}}
+		res, err := Resolve(context.Background(), mock, evt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprintf("%T", res); got != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, got)
+		}
+	}
+	evt := &events.Event{ID: "abc", Links: map[string]client.Link{"resource": {Href: "https://api-sandbox.dwolla.com/labels/abc"}}}
+	_, err := Resolve(context.Background(), mock, evt)
+	if err == nil {
+		t.Error("expected an error for an unsupported resource")
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"code": "NotFound", "message": "The requested resource was not found."}`)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	for _, collection := range []string{"customers", "transfers", "funding-sources", "documents", "mass-payments"} {
+		evt := &events.Event{ID: "abc", Links: map[string]client.Link{"resource": {Href: "https://api-sandbox.dwolla.com/" + collection + "/abc"}}}
+		res, err := Resolve(context.Background(), mock, evt)
+		if err == nil || res != nil {
+			t.Errorf("%s: expected a nil resource and an error, got %#v and %v", collection, res, err)
+		}
+	}
+}
+
+func TestHandlerOnTransferCompleted(t *testing.T) {
+	ts := resourceServer(t)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	h := NewHandler(mockSecret, WithClient(mock))
+	var got *transfer.Transfer
+	h.OnTransferCompleted(func(ctx context.Context, evt *events.Event, tr *transfer.Transfer) error {
+		got = tr
+		return nil
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(mockEvent, mockSecret))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got == nil || got.ID != "d0d5c0d3-e2b1-e811-8112-e8dd3bececa8" {
+		t.Errorf("unexpected transfer %+v", got)
+	}
+}
+
+func TestHandlerOnCustomerWrongResource(t *testing.T) {
+	ts := resourceServer(t)
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	h := NewHandler(mockSecret, WithClient(mock))
+	h.OnCustomer(events.TopicTransferCompleted, func(ctx context.Context, evt *events.Event, cu *customer.Customer) error {
+		t.Error("unexpected call with a transfer event")
+		return nil
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(mockEvent, mockSecret))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// SeenStore records the IDs of the events that were handled, so that
+// the duplicate deliveries of an event are not handled again.
+type SeenStore interface {
+	// Seen reports whether the event with ID eventID was handled.
+	Seen(ctx context.Context, eventID string) (bool, error)
+	// MarkSeen records that the event with ID eventID was handled.
+	MarkSeen(ctx context.Context, eventID string) error
+}
+
+// MemorySeenStore is a SeenStore kept in memory. It remembers a bounded
+// number of event IDs, each for a limited time, and forgets the least
+// recently seen ones first.
+type MemorySeenStore struct {
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+	mu    sync.Mutex
+	order *list.List // Front is the most recently seen event
+	items map[string]*list.Element
+}
+
+type seenEntry struct {
+	id      string
+	expires time.Time
+}
+
+// Defaults of NewMemorySeenStore, used for a size or ttl that is not positive.
+const (
+	DefaultSeenStoreSize = 10000
+	DefaultSeenTTL       = 72 * time.Hour
+)
+
+// NewMemorySeenStore returns a MemorySeenStore that remembers up to size
+// event IDs for ttl each. Dwolla retries a webhook for up to 72 hours,
+// so a ttl of 72 hours covers all the deliveries of an event.
+// A size or ttl that is not positive is replaced by DefaultSeenStoreSize
+// or DefaultSeenTTL.
+func NewMemorySeenStore(size int, ttl time.Duration) *MemorySeenStore {
+	if size <= 0 {
+		size = DefaultSeenStoreSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultSeenTTL
+	}
+	return &MemorySeenStore{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Seen implements SeenStore.
+func (m *MemorySeenStore) Seen(ctx context.Context, eventID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[eventID]
+	if !ok {
+		return false, nil
+	}
+	if m.now().After(el.Value.(*seenEntry).expires) {
+		m.remove(el)
+		return false, nil
+	}
+	m.order.MoveToFront(el)
+	return true, nil
+}
+
+// MarkSeen implements SeenStore.
+func (m *MemorySeenStore) MarkSeen(ctx context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expires := m.now().Add(m.ttl)
+	if el, ok := m.items[eventID]; ok {
+		el.Value.(*seenEntry).expires = expires
+		m.order.MoveToFront(el)
+		return nil
+	}
+	m.items[eventID] = m.order.PushFront(&seenEntry{id: eventID, expires: expires})
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+// Len returns the number of event IDs in the store, including expired ones
+// that were not removed yet.
+func (m *MemorySeenStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *MemorySeenStore) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.items, el.Value.(*seenEntry).id)
+}
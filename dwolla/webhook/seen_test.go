@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+)
+
+func TestMemorySeenStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2018, 11, 6, 15, 4, 5, 0, time.UTC)
+	s := NewMemorySeenStore(2, time.Hour)
+	s.now = func() time.Time { return now }
+	s.MarkSeen(ctx, "a")
+	s.MarkSeen(ctx, "b")
+	s.Seen(ctx, "a") // a is now the most recently seen
+	s.MarkSeen(ctx, "c")
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if seen, _ := s.Seen(ctx, id); seen != want {
+			t.Errorf("%s: expected seen to be %v", id, want)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if seen, _ := s.Seen(ctx, "a"); seen {
+		t.Error("expected an expired event not to be seen")
+	}
+	if s.Len() != 1 {
+		t.Errorf("expected the expired event to be removed, got %d events", s.Len())
+	}
+}
+
+func TestMemorySeenStoreDefaults(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySeenStore(0, -time.Hour)
+	s.MarkSeen(ctx, "a")
+	if seen, _ := s.Seen(ctx, "a"); !seen || s.size != DefaultSeenStoreSize || s.ttl != DefaultSeenTTL {
+		t.Errorf("expected the defaults to be used, got size %d and ttl %v", s.size, s.ttl)
+	}
+}
+
+func TestHandlerSeenStore(t *testing.T) {
+	h := NewHandler(mockSecret, WithSeenStore(NewMemorySeenStore(100, time.Hour)))
+	var calls int
+	h.On("transfer_completed", func(ctx context.Context, evt *events.Event) error {
+		calls++
+		return nil
+	})
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, signedRequest(mockEvent, mockSecret))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the handler to be called once, got %d calls", calls)
+	}
+}
+
+func TestHandlerConcurrentDuplicates(t *testing.T) {
+	h := NewHandler(mockSecret, WithSeenStore(NewMemorySeenStore(100, time.Hour)))
+	var calls int32
+	release := make(chan struct{})
+	h.On("transfer_completed", func(ctx context.Context, evt *events.Event) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil
+	})
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(mockEvent, mockSecret))
+			codes[i] = w.Code
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("expected the handler to be called once, got %d calls", calls)
+	}
+	for _, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected 200, got %d", code)
+		}
+	}
+}
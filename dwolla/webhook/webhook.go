@@ -0,0 +1,300 @@
+// Package webhook provides methods to manage webhook subscriptions via the dwolla api
+// and an http.Handler to receive the webhooks dwolla delivers.
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/pkg/errors"
+)
+
+// Subscription is a webhook subscription. Dwolla delivers a webhook
+// to the subscription url for every event of the account.
+type Subscription struct {
+	Client    client.DwollaClient    `json:"-"`
+	Links     map[string]client.Link `json:"_links"`
+	ID        string                 `json:"id"`
+	URL       string                 `json:"url"`
+	Paused    bool                   `json:"paused"`
+	CreatedAt time.Time              `json:"created"`
+}
+
+// Webhook is a notification of an event delivered to a subscription.
+type Webhook struct {
+	Client         client.DwollaClient    `json:"-"`
+	Links          map[string]client.Link `json:"_links"`
+	ID             string                 `json:"id"`
+	Topic          events.Topic           `json:"topic"`
+	AccountID      string                 `json:"accountId"`
+	EventID        string                 `json:"eventId"`
+	SubscriptionID string                 `json:"subscriptionId"`
+	Attempts       []Attempt              `json:"attempts"`
+}
+
+// Attempt is a delivery of a webhook, with the request dwolla sent
+// and the response of the subscription url.
+type Attempt struct {
+	ID       string          `json:"id"`
+	Request  AttemptRequest  `json:"request"`
+	Response AttemptResponse `json:"response"`
+}
+
+// AttemptRequest is the request dwolla sent to deliver a webhook.
+type AttemptRequest struct {
+	Timestamp time.Time `json:"timestamp"`
+	URL       string    `json:"url"`
+	Headers   []Header  `json:"headers"`
+	Body      string    `json:"body"`
+}
+
+// AttemptResponse is the response of the subscription url to a webhook.
+type AttemptResponse struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Headers    []Header  `json:"headers"`
+	StatusCode int       `json:"statusCode"`
+	Body       string    `json:"body"`
+}
+
+// Header is an http header of an attempt.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Retry is a redelivery of a webhook that was requested via the dwolla api.
+type Retry struct {
+	Links     map[string]client.Link `json:"_links"`
+	ID        string                 `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// ListSubscriptionsResponse is the response that is returned by dwolla
+// to list webhook subscriptions.
+type ListSubscriptionsResponse struct {
+	client.Page
+	Embedded map[string][]Subscription `json:"_embedded"`
+}
+
+// ListWebhooksResponse is the response that is returned by dwolla
+// to list the webhooks of a subscription.
+type ListWebhooksResponse struct {
+	client.Page
+	Embedded map[string][]Webhook `json:"_embedded"`
+}
+
+// ListRetriesResponse is the response that is returned by dwolla
+// to list the retries of a webhook.
+type ListRetriesResponse struct {
+	client.Page
+	Embedded map[string][]Retry `json:"_embedded"`
+}
+
+// Iterator iterates over the webhooks of a subscription,
+// following the next link of each page.
+type Iterator struct {
+	pages *client.PageIterator
+	items []Webhook
+}
+
+type createSubscriptionRequest struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// CreateSubscription subscribes url to the webhooks of the account and returns
+// the ID and href of the new subscription. secret is used by dwolla to sign
+// each webhook, it must be kept to verify them.
+func CreateSubscription(ctx context.Context, c client.DwollaClient, url, secret string) (*client.Created, error) {
+	return client.Create(ctx, c, "/webhook-subscriptions", &createSubscriptionRequest{URL: url, Secret: secret})
+}
+
+// ListSubscriptions retrieves the webhook subscriptions of the account.
+// It also returns the number of subscriptions.
+func ListSubscriptions(ctx context.Context, c client.DwollaClient) ([]Subscription, int, error) {
+	body := &ListSubscriptionsResponse{}
+	_, err := client.Do(ctx, c, "GET", "/webhook-subscriptions", nil, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	subs := body.Embedded["webhook-subscriptions"]
+	for i := range subs {
+		subs[i].Client = c
+	}
+	return subs, body.Total, nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID.
+func GetSubscription(ctx context.Context, c client.DwollaClient, subscriptionID string) (*Subscription, error) {
+	body := &Subscription{}
+	_, err := client.Do(ctx, c, "GET", "/webhook-subscriptions/"+subscriptionID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	body.Client = c
+	return body, nil
+}
+
+// Pause stops the delivery of webhooks to the subscription.
+func (s *Subscription) Pause(ctx context.Context) error {
+	return s.setPaused(ctx, true)
+}
+
+// Unpause resumes the delivery of webhooks to the subscription.
+func (s *Subscription) Unpause(ctx context.Context) error {
+	return s.setPaused(ctx, false)
+}
+
+func (s *Subscription) setPaused(ctx context.Context, paused bool) error {
+	req := map[string]bool{"paused": paused}
+	_, err := client.Do(ctx, s.Client, "POST", "/webhook-subscriptions/"+s.ID, req, nil)
+	if err != nil {
+		return err
+	}
+	s.Paused = paused
+	return nil
+}
+
+// Delete the subscription. No webhook is delivered to its url afterwards.
+func (s *Subscription) Delete(ctx context.Context) error {
+	_, err := client.Do(ctx, s.Client, "DELETE", "/webhook-subscriptions/"+s.ID, nil, nil)
+	return err
+}
+
+// ListWebhooks retrieves a page of the webhooks delivered to the subscription,
+// newest first. It also returns the number of webhooks in the whole list.
+func (s *Subscription) ListWebhooks(ctx context.Context, opts *client.ListOptions) ([]Webhook, int, error) {
+	webhooks, page, err := listPage(ctx, s.Client, s.webhooksPath(opts))
+	if err != nil {
+		return nil, 0, err
+	}
+	return webhooks, page.Total, nil
+}
+
+// IterWebhooks returns an iterator over all the webhooks delivered to the subscription.
+// opts selects the size of the pages and where the iteration starts.
+func (s *Subscription) IterWebhooks(ctx context.Context, opts *client.ListOptions) *Iterator {
+	c := s.Client
+	it := &Iterator{}
+	it.pages = client.NewPageIterator(ctx, s.webhooksPath(opts), func(ctx context.Context, href string) (client.Page, int, error) {
+		webhooks, page, err := listPage(ctx, c, href)
+		it.items = webhooks
+		return page, len(webhooks), err
+	})
+	return it
+}
+
+// Next advances to the next webhook. It returns false when
+// there are no more webhooks or a request failed.
+func (it *Iterator) Next() bool {
+	return it.pages.Next()
+}
+
+// Webhook returns the current webhook.
+func (it *Iterator) Webhook() *Webhook {
+	return &it.items[it.pages.Index()]
+}
+
+// Total returns the number of webhooks in the whole list.
+func (it *Iterator) Total() int {
+	return it.pages.Total()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.pages.Err()
+}
+
+func (s *Subscription) webhooksPath(opts *client.ListOptions) string {
+	return client.WithQuery("/webhook-subscriptions/"+s.ID+"/webhooks", opts.Values())
+}
+
+func listPage(ctx context.Context, c client.DwollaClient, href string) ([]Webhook, client.Page, error) {
+	body := &ListWebhooksResponse{}
+	_, err := client.Do(ctx, c, "GET", href, nil, body)
+	if err != nil {
+		return nil, client.Page{}, err
+	}
+	webhooks := body.Embedded["webhooks"]
+	for i := range webhooks {
+		webhooks[i].Client = c
+	}
+	return webhooks, body.Page, nil
+}
+
+// GetWebhook retrieves a webhook by ID, with its delivery attempts.
+func GetWebhook(ctx context.Context, c client.DwollaClient, webhookID string) (*Webhook, error) {
+	body := &Webhook{}
+	_, err := client.Do(ctx, c, "GET", "/webhooks/"+webhookID, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	body.Client = c
+	return body, nil
+}
+
+// Failed reports whether no attempt to deliver the webhook got a 2xx response.
+func (w *Webhook) Failed() bool {
+	for _, a := range w.Attempts {
+		if a.Response.StatusCode >= 200 && a.Response.StatusCode < 300 {
+			return false
+		}
+	}
+	return true
+}
+
+// ListRetries retrieves the retries requested for the webhook.
+// It also returns the number of retries.
+func (w *Webhook) ListRetries(ctx context.Context) ([]Retry, int, error) {
+	body := &ListRetriesResponse{}
+	_, err := client.Do(ctx, w.Client, "GET", "/webhooks/"+w.ID+"/retries", nil, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body.Embedded["retries"], body.Total, nil
+}
+
+// Retry requests dwolla to deliver the webhook again.
+// It returns the ID and href of the new retry.
+func (w *Webhook) Retry(ctx context.Context) (*client.Created, error) {
+	return client.Create(ctx, w.Client, "/webhooks/"+w.ID+"/retries", nil)
+}
+
+// RetryFailed requests the redelivery of every failed webhook of the subscription
+// first delivered between since and until. It returns the retried webhooks.
+// Webhooks are listed newest first, so the listing stops at the first webhook
+// delivered before since. On error, the webhooks retried so far are returned
+// with the error.
+func (s *Subscription) RetryFailed(ctx context.Context, since, until time.Time) ([]Webhook, error) {
+	var retried []Webhook
+	it := s.IterWebhooks(ctx, &client.ListOptions{Limit: 200})
+	for it.Next() {
+		w := it.Webhook()
+		if len(w.Attempts) == 0 {
+			continue
+		}
+		delivered := w.Attempts[len(w.Attempts)-1].Request.Timestamp
+		for _, a := range w.Attempts {
+			if a.Request.Timestamp.Before(delivered) {
+				delivered = a.Request.Timestamp
+			}
+		}
+		if delivered.Before(since) {
+			break
+		}
+		if delivered.After(until) || !w.Failed() {
+			continue
+		}
+		_, err := w.Retry(ctx)
+		if err != nil {
+			return retried, errors.Wrapf(err, "error retrying webhook %s", w.ID)
+		}
+		retried = append(retried, *w)
+	}
+	if err := it.Err(); err != nil {
+		return retried, errors.Wrap(err, "error listing webhooks")
+	}
+	return retried, nil
+}
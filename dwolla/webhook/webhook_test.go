@@ -0,0 +1,338 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var mockSubscription = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/077dfffb-4852-412f-96b6-0fe668066589"
+    },
+    "webhooks": {
+      "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/077dfffb-4852-412f-96b6-0fe668066589/webhooks"
+    }
+  },
+  "id": "077dfffb-4852-412f-96b6-0fe668066589",
+  "url": "http://myawesomeapplication.com/destination",
+  "paused": false,
+  "created": "2015-10-28T16:20:47+00:00"
+}
+`
+
+var mockSubscriptions = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/webhook-subscriptions"
+    }
+  },
+  "_embedded": {
+    "webhook-subscriptions": [
+      {
+        "_links": {
+          "self": {
+            "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/077dfffb-4852-412f-96b6-0fe668066589"
+          },
+          "webhooks": {
+            "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/077dfffb-4852-412f-96b6-0fe668066589/webhooks"
+          }
+        },
+        "id": "077dfffb-4852-412f-96b6-0fe668066589",
+        "url": "http://myapplication.com/webhooks",
+        "paused": false,
+        "created": "2015-08-19T21:43:49.000Z"
+      }
+    ]
+  },
+  "total": 1
+}
+`
+
+var mockWebhooks = `
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/10d4133e-b308-4646-b276-40d9d36def1c/webhooks"
+    },
+    "first": {
+      "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/10d4133e-b308-4646-b276-40d9d36def1c/webhooks?limit=25&offset=0"
+    }
+  },
+  "_embedded": {
+    "webhooks": [
+      {
+        "_links": {
+          "self": {
+            "href": "https://api-sandbox.dwolla.com/webhooks/4b4b4a1c-9a5f-4a7f-ae5e-2d6d1e6e86a3"
+          },
+          "subscription": {
+            "href": "https://api-sandbox.dwolla.com/webhook-subscriptions/10d4133e-b308-4646-b276-40d9d36def1c"
+          },
+          "retry": {
+            "href": "https://api-sandbox.dwolla.com/webhooks/4b4b4a1c-9a5f-4a7f-ae5e-2d6d1e6e86a3/retries"
+          },
+          "event": {
+            "href": "https://api-sandbox.dwolla.com/events/f8e70f48-b7ff-47d0-9d3d-62a099363a76"
+          }
+        },
+        "id": "4b4b4a1c-9a5f-4a7f-ae5e-2d6d1e6e86a3",
+        "topic": "transfer_created",
+        "accountId": "ca32853c-48fa-40be-ae75-77b37504581b",
+        "eventId": "f8e70f48-b7ff-47d0-9d3d-62a099363a76",
+        "subscriptionId": "10d4133e-b308-4646-b276-40d9d36def1c"
+      }
+    ]
+  },
+  "total": 1
+}
+`
+
+type mockClient struct {
+	authToken string
+	rootURL   string
+}
+
+func (m *mockClient) RootURL() string {
+	return m.rootURL
+}
+
+func (m *mockClient) Root(ctx context.Context) (map[string]map[string]string, error) {
+	return m.Links(ctx)
+}
+
+func (m *mockClient) AuthToken(ctx context.Context) (string, error) {
+	return m.authToken, nil
+}
+
+func (m *mockClient) Links(ctx context.Context) (map[string]map[string]string, error) {
+	mockLinks := make(map[string]map[string]string)
+	mockLinks["self"] = map[string]string{"href": m.rootURL}
+	mockLinks["account"] = map[string]string{"href": m.rootURL + "/account"}
+	return mockLinks, nil
+}
+
+func (m *mockClient) SetAccessToken(ctx context.Context) error {
+	return nil
+}
+
+func (m *mockClient) SetRootURL(url string) {
+	m.rootURL = url
+}
+
+func (m *mockClient) Send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+m.authToken)
+	return http.DefaultClient.Do(req)
+}
+
+func stubClient() *mockClient {
+	return &mockClient{
+		authToken: "abcdefghijklmn",
+		rootURL:   "http://localhost:8080",
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	var got createSubscriptionRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Location", "https://api-sandbox.dwolla.com/webhook-subscriptions/5af4c10a-f6de-4ac8-840d-42cb65454216")
+		w.WriteHeader(201)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	created, err := CreateSubscription(context.Background(), mock, "https://example.com/webhooks", "sshhhhhh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "5af4c10a-f6de-4ac8-840d-42cb65454216" {
+		t.Errorf("unexpected subscription id %q", created.ID)
+	}
+	if got.URL != "https://example.com/webhooks" || got.Secret != "sshhhhhh" {
+		t.Errorf("unexpected request body %+v", got)
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockSubscriptions)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	subs, total, err := ListSubscriptions(context.Background(), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(subs) != 1 || subs[0].Client == nil {
+		t.Errorf("expected a subscription with a client, got %+v", subs)
+	}
+}
+
+func TestPauseAndDeleteSubscription(t *testing.T) {
+	var methods []string
+	var paused map[string]bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(&paused)
+		}
+		fmt.Fprint(w, mockSubscription)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	sub, err := GetSubscription(context.Background(), mock, "077dfffb-4852-412f-96b6-0fe668066589")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sub.Pause(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sub.Paused || !paused["paused"] {
+		t.Error("expected the subscription to be paused")
+	}
+	err = sub.Delete(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) != 3 || methods[2] != "DELETE" {
+		t.Errorf("unexpected requests %v", methods)
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhook-subscriptions/077dfffb-4852-412f-96b6-0fe668066589" {
+			fmt.Fprint(w, mockSubscription)
+			return
+		}
+		fmt.Fprint(w, mockWebhooks)
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	sub, err := GetSubscription(context.Background(), mock, "077dfffb-4852-412f-96b6-0fe668066589")
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks, total, err := sub.ListWebhooks(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || webhooks[0].Topic != "transfer_created" || webhooks[0].Links["event"].Href == "" {
+		t.Errorf("unexpected webhooks %+v", webhooks)
+	}
+}
+
+func mockWebhookJSON(id string, status int, timestamp string) string {
+	return fmt.Sprintf(`
+{
+  "_links": {
+    "self": {
+      "href": "https://api-sandbox.dwolla.com/webhooks/%[1]s"
+    }
+  },
+  "id": "%[1]s",
+  "topic": "transfer_created",
+  "accountId": "ca32853c-48fa-40be-ae75-77b37504581b",
+  "eventId": "f8e70f48-b7ff-47d0-9d3d-62a099363a76",
+  "subscriptionId": "077dfffb-4852-412f-96b6-0fe668066589",
+  "attempts": [
+    {
+      "id": "5aa27a0f-cf99-418d-a3ee-67c0ff99a494",
+      "request": {
+        "timestamp": "%[3]s",
+        "url": "http://myapplication.com/webhooks",
+        "headers": [
+          {
+            "name": "X-Request-Signature-SHA-256",
+            "value": "a4ef5d7dd5ca1ae4d7b4fd1af1a1d7c2a1aa3b3e2e4e1c4e5a4e7f6c3a3b3e2e"
+          }
+        ],
+        "body": "{\"id\":\"f8e70f48-b7ff-47d0-9d3d-62a099363a76\"}"
+      },
+      "response": {
+        "timestamp": "%[3]s",
+        "headers": [
+          {
+            "name": "Content-Type",
+            "value": "text/plain"
+          }
+        ],
+        "statusCode": %[2]d,
+        "body": ""
+      }
+    }
+  ]
+}`, id, status, timestamp)
+}
+
+func TestGetWebhook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mockWebhookJSON("4b4b4a1c-9a5f-4a7f-ae5e-2d6d1e6e86a3", 500, "2018-11-06T15:04:05.000Z"))
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	wh, err := GetWebhook(context.Background(), mock, "4b4b4a1c-9a5f-4a7f-ae5e-2d6d1e6e86a3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wh.Attempts) != 1 {
+		t.Fatalf("expected an attempt, got %+v", wh.Attempts)
+	}
+	a := wh.Attempts[0]
+	if a.Request.Headers[0].Name != "X-Request-Signature-SHA-256" || a.Response.StatusCode != 500 || a.Request.Timestamp.IsZero() {
+		t.Errorf("unexpected attempt %+v", a)
+	}
+	if !wh.Failed() {
+		t.Error("expected the webhook to have failed")
+	}
+}
+
+func TestRetryFailed(t *testing.T) {
+	webhooks := []string{
+		mockWebhookJSON("too-new", 500, "2018-11-08T12:00:00.000Z"),
+		mockWebhookJSON("failed", 500, "2018-11-06T12:00:00.000Z"),
+		mockWebhookJSON("delivered", 200, "2018-11-06T11:00:00.000Z"),
+		mockWebhookJSON("too-old", 500, "2018-11-04T12:00:00.000Z"),
+	}
+	var retried []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			retried = append(retried, r.URL.Path)
+			w.Header().Set("Location", "https://api-sandbox.dwolla.com/retries/5aa27a0f-cf99-418d-a3ee-67c0ff99a494")
+			w.WriteHeader(201)
+		default:
+			fmt.Fprintf(w, `{"_links": {}, "_embedded": {"webhooks": [%s]}, "total": %d}`, strings.Join(webhooks, ","), len(webhooks))
+		}
+	}))
+	defer ts.Close()
+	mock := stubClient()
+	mock.SetRootURL(ts.URL)
+	sub := &Subscription{Client: mock, ID: "077dfffb-4852-412f-96b6-0fe668066589"}
+	since := time.Date(2018, 11, 5, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2018, 11, 7, 0, 0, 0, 0, time.UTC)
+	got, err := sub.RetryFailed(context.Background(), since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "failed" {
+		t.Errorf("unexpected retried webhooks %+v", got)
+	}
+	if len(retried) != 1 || retried[0] != "/webhooks/failed/retries" {
+		t.Errorf("unexpected retry requests %v", retried)
+	}
+}
@@ -0,0 +1,176 @@
+// Package webhooktest builds signed dwolla webhooks to test the processing
+// of webhooks end to end, without connectivity to dwolla.
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/client"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/webhook"
+	"github.com/pkg/errors"
+)
+
+// DefaultBaseURL is the url of the links of the events built by a Simulator,
+// unless its BaseURL is set.
+const DefaultBaseURL = "https://api-sandbox.dwolla.com"
+
+// Simulator builds webhooks signed with the secret of a webhook subscription
+// and delivers them to an http.Handler or a url.
+type Simulator struct {
+	// Secret signs the webhooks, as the secret of a webhook subscription.
+	Secret string
+	// BaseURL prefixes the links of the events. Set it to the url of a test
+	// server to have the resources of the events retrieved from it.
+	BaseURL string
+	// AccountID is the ID of the account the events belong to.
+	AccountID string
+}
+
+// EventOption configures an event built by a Simulator.
+type EventOption func(*events.Event)
+
+// NewSimulator returns a Simulator that signs webhooks with secret.
+func NewSimulator(secret string) *Simulator {
+	return &Simulator{
+		Secret:    secret,
+		BaseURL:   DefaultBaseURL,
+		AccountID: "ca32853c-48fa-40be-ae75-77b37504581b",
+	}
+}
+
+// WithCustomer sets the customer the resource of the event belongs to.
+// customer is the ID or the href of the customer.
+func WithCustomer(customer string) EventOption {
+	return func(evt *events.Event) {
+		evt.Links["customer"] = client.Link{Href: customer}
+	}
+}
+
+// WithEventID sets the ID of the event, instead of a random one.
+func WithEventID(id string) EventOption {
+	return func(evt *events.Event) {
+		evt.ID = id
+	}
+}
+
+// WithTimestamp sets the time of the event, instead of the current time.
+func WithTimestamp(t time.Time) EventOption {
+	return func(evt *events.Event) {
+		evt.Timestamp = t
+		evt.CreatedAt = t
+	}
+}
+
+// NewEvent builds an event of topic about the resource at resource,
+// such as "/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8". A path is
+// resolved against the BaseURL of the simulator.
+func (s *Simulator) NewEvent(topic events.Topic, resource string, opts ...EventOption) *events.Event {
+	now := time.Now().UTC()
+	resourceHref := s.href(resource)
+	evt := &events.Event{
+		ID:         newID(),
+		Topic:      topic,
+		ResourceID: path.Base(resourceHref),
+		Timestamp:  now,
+		CreatedAt:  now,
+		Links: map[string]client.Link{
+			"account":  {Href: s.href("/accounts/" + s.AccountID)},
+			"resource": {Href: resourceHref},
+		},
+	}
+	if strings.Contains(resourceHref, "/customers/") {
+		evt.Links["customer"] = client.Link{Href: resourceHref}
+	}
+	for _, opt := range opts {
+		opt(evt)
+	}
+	if l, ok := evt.Links["customer"]; ok {
+		if !strings.Contains(l.Href, "/") {
+			l.Href = "/customers/" + l.Href
+		}
+		evt.Links["customer"] = client.Link{Href: s.href(l.Href)}
+	}
+	evt.Links["self"] = client.Link{Href: s.href("/events/" + evt.ID)}
+	return evt
+}
+
+// Payload returns the body of the webhook of evt and its signature.
+func (s *Simulator) Payload(evt *events.Event) ([]byte, string, error) {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error marshalling the event")
+	}
+	return body, webhook.Sign(s.Secret, body), nil
+}
+
+// NewRequest returns a signed webhook request of evt, to be passed to an http.Handler.
+func (s *Simulator) NewRequest(evt *events.Event) (*http.Request, error) {
+	body, sig, err := s.Payload(evt)
+	if err != nil {
+		return nil, err
+	}
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.SignatureHeader, sig)
+	return req, nil
+}
+
+// Serve delivers the webhook of evt to h and returns the response of h.
+func (s *Simulator) Serve(h http.Handler, evt *events.Event) (*http.Response, error) {
+	req, err := s.NewRequest(evt)
+	if err != nil {
+		return nil, err
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Result(), nil
+}
+
+// Post delivers the webhook of evt to url and returns the response.
+// The caller must close the body of the response.
+func (s *Simulator) Post(ctx context.Context, url string, evt *events.Event) (*http.Response, error) {
+	body, sig, err := s.Payload(evt)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the webhook request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.SignatureHeader, sig)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error posting the webhook")
+	}
+	return res, nil
+}
+
+func (s *Simulator) href(hrefOrPath string) string {
+	if strings.HasPrefix(hrefOrPath, "http://") || strings.HasPrefix(hrefOrPath, "https://") {
+		return hrefOrPath
+	}
+	base := s.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(hrefOrPath, "/")
+}
+
+// newID returns a random ID formatted like the IDs of dwolla.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
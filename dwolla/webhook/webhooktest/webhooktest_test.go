@@ -0,0 +1,67 @@
+package webhooktest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/events"
+	"github.com/ahmedaabouzied/dwolla-go/dwolla/webhook"
+)
+
+const testSecret = "your webhook secret"
+
+func TestNewEvent(t *testing.T) {
+	s := NewSimulator(testSecret)
+	evt := s.NewEvent(events.TopicCustomerTransferCompleted, "/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8",
+		WithCustomer("fc451a7a-ae30-4404-ab95-e3553fcd733f"))
+	if evt.ResourceID != "d0d5c0d3-e2b1-e811-8112-e8dd3bececa8" {
+		t.Errorf("unexpected resource id %q", evt.ResourceID)
+	}
+	if evt.ResourceHref() != "https://api-sandbox.dwolla.com/transfers/d0d5c0d3-e2b1-e811-8112-e8dd3bececa8" {
+		t.Errorf("unexpected resource href %q", evt.ResourceHref())
+	}
+	if evt.CustomerHref() != "https://api-sandbox.dwolla.com/customers/fc451a7a-ae30-4404-ab95-e3553fcd733f" {
+		t.Errorf("unexpected customer href %q", evt.CustomerHref())
+	}
+	if evt.ID == "" || evt.ID == s.NewEvent(events.TopicTransferCompleted, "/transfers/abc").ID {
+		t.Errorf("expected a random event id, got %q", evt.ID)
+	}
+}
+
+func TestPayloadSignature(t *testing.T) {
+	s := NewSimulator(testSecret)
+	body, sig, err := s.Payload(s.NewEvent(events.TopicCustomerCreated, "/customers/fc451a7a-ae30-4404-ab95-e3553fcd733f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !webhook.VerifySignature(testSecret, body, sig) {
+		t.Error("expected a valid signature")
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got *events.Event
+	h := webhook.NewHandler(testSecret)
+	h.Default(func(ctx context.Context, evt *events.Event) error {
+		got = evt
+		return nil
+	})
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	s := NewSimulator(testSecret)
+	res, err := s.Post(context.Background(), ts.URL, s.NewEvent(events.TopicCustomerCreated, "/customers/fc451a7a-ae30-4404-ab95-e3553fcd733f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	if got == nil || got.Topic != events.TopicCustomerCreated {
+		t.Errorf("unexpected event %+v", got)
+	}
+}